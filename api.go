@@ -0,0 +1,114 @@
+package sdk
+
+import (
+	"context"
+	"io/fs"
+)
+
+// API 聚合全部服务接口，由 *Client 实现
+//
+// 业务代码依赖 API 而不是 *Client 时，可以在测试中注入 sdkmock.Client 等替代实现。
+type API interface {
+	ModelsAPI() ModelsAPI
+	DatasetsAPI() DatasetsAPI
+	DocumentsAPI() DocumentsAPI
+	SearchAPI() SearchAPI
+	QAAPI() QAAPI
+	GenerateAPI() GenerateAPI
+	HealthAPI() HealthAPI
+}
+
+// ModelsAPI AI 模型管理接口，由 *ModelsService 实现
+type ModelsAPI interface {
+	Create(ctx context.Context, req *CreateModelRequest, opts ...CallOption) (*AIModel, error)
+	List(ctx context.Context, req *ListModelsRequest, opts ...CallOption) (*ListModelsResponse, error)
+	ListPager(req *ListModelsRequest, opts *PagerOptions, callOpts ...CallOption) *Pager[AIModel]
+	Get(ctx context.Context, modelID string, opts ...CallOption) (*AIModel, error)
+	Update(ctx context.Context, modelID string, req *UpdateModelRequest, opts ...CallOption) (*AIModel, error)
+	Delete(ctx context.Context, modelID string, opts ...CallOption) error
+	ListProviderModels(ctx context.Context, req *ListProviderModelsRequest, opts ...CallOption) (interface{}, error)
+	Check(ctx context.Context, req *CheckModelRequest, opts ...CallOption) (*CheckModelResponse, error)
+	Upsert(ctx context.Context, req *UpsertModelRequest, opts ...CallOption) (*UpsertModelResponse, error)
+}
+
+// DatasetsAPI 数据集管理接口，由 *DatasetsService 实现
+type DatasetsAPI interface {
+	Create(ctx context.Context, req *CreateDatasetRequest, opts ...CallOption) (*Dataset, error)
+	List(ctx context.Context, req *ListDatasetsRequest, opts ...CallOption) (*ListDatasetsResponse, error)
+	ListPager(req *ListDatasetsRequest, opts *PagerOptions, callOpts ...CallOption) *Pager[Dataset]
+	Get(ctx context.Context, datasetID string, opts ...CallOption) (*Dataset, error)
+	Update(ctx context.Context, datasetID string, req *UpdateDatasetRequest, opts ...CallOption) (*Dataset, error)
+	Delete(ctx context.Context, datasetID string, opts ...CallOption) error
+	GetStats(ctx context.Context, datasetID string, opts ...CallOption) (*DatasetStats, error)
+}
+
+// DocumentsAPI 文档管理接口，由 *DocumentsService 实现
+type DocumentsAPI interface {
+	Upload(ctx context.Context, req *UploadDocumentRequest, opts ...CallOption) (*UploadDocumentResponse, error)
+	List(ctx context.Context, req *ListDocumentsRequest, opts ...CallOption) (*ListDocumentsResponse, error)
+	ListPager(req *ListDocumentsRequest, opts *PagerOptions, callOpts ...CallOption) *Pager[Document]
+	Get(ctx context.Context, datasetID, documentID string, opts ...CallOption) (*Document, error)
+	Update(ctx context.Context, req *UpdateDocumentRequest, opts ...CallOption) (*Document, error)
+	Delete(ctx context.Context, datasetID, documentID string, opts ...CallOption) error
+	BatchDelete(ctx context.Context, req *BatchDeleteDocumentsRequest, opts ...CallOption) error
+	Reindex(ctx context.Context, datasetID, documentID string, opts ...CallOption) (*ReindexResponse, error)
+	WaitUntilProcessed(ctx context.Context, datasetID, documentID string, opts *WaitOptions, callOpts ...CallOption) (*Document, error)
+	WaitUntilAllProcessed(ctx context.Context, datasetID string, documentIDs []string, opts *WaitOptions, callOpts ...CallOption) ([]Document, error)
+	UploadMany(ctx context.Context, reqs <-chan *UploadDocumentRequest, opts *BulkUploadOptions, callOpts ...CallOption) (*BulkUploadReport, error)
+	SyncDir(ctx context.Context, datasetID, dir string, opts *SyncOptions, callOpts ...CallOption) (*SyncPlan, error)
+	SyncFS(ctx context.Context, datasetID string, fsys fs.FS, opts *SyncOptions, callOpts ...CallOption) (*SyncPlan, error)
+}
+
+// SearchAPI 搜索接口，由 *SearchService 实现
+type SearchAPI interface {
+	Retrieve(ctx context.Context, req *RetrieveRequest, opts ...CallOption) (*SearchResponse, error)
+}
+
+// QAAPI 问答接口，由 *QAService 实现
+type QAAPI interface {
+	Ask(ctx context.Context, req *QARequest, opts ...CallOption) (*QAResponse, error)
+	AskStream(ctx context.Context, req *QARequest, opts ...CallOption) (*QAStream, error)
+}
+
+// GenerateAPI 生成接口，由 *GenerateService 实现
+type GenerateAPI interface {
+	Generate(ctx context.Context, req *GenerateRequest, opts ...CallOption) (*GenerateResponse, error)
+	GenerateStream(ctx context.Context, req *GenerateRequest, opts ...CallOption) (*GenerateStream, error)
+}
+
+// HealthAPI 健康检查接口，由 *HealthService 实现
+type HealthAPI interface {
+	Check(ctx context.Context, opts ...CallOption) (*HealthResponse, error)
+}
+
+var (
+	_ API          = (*Client)(nil)
+	_ ModelsAPI    = (*ModelsService)(nil)
+	_ DatasetsAPI  = (*DatasetsService)(nil)
+	_ DocumentsAPI = (*DocumentsService)(nil)
+	_ SearchAPI    = (*SearchService)(nil)
+	_ QAAPI        = (*QAService)(nil)
+	_ GenerateAPI  = (*GenerateService)(nil)
+	_ HealthAPI    = (*HealthService)(nil)
+)
+
+// ModelsAPI 返回模型管理服务
+func (c *Client) ModelsAPI() ModelsAPI { return c.Models }
+
+// DatasetsAPI 返回数据集管理服务
+func (c *Client) DatasetsAPI() DatasetsAPI { return c.Datasets }
+
+// DocumentsAPI 返回文档管理服务
+func (c *Client) DocumentsAPI() DocumentsAPI { return c.Documents }
+
+// SearchAPI 返回搜索服务
+func (c *Client) SearchAPI() SearchAPI { return c.Search }
+
+// QAAPI 返回问答服务
+func (c *Client) QAAPI() QAAPI { return c.QA }
+
+// GenerateAPI 返回生成服务
+func (c *Client) GenerateAPI() GenerateAPI { return c.Generate }
+
+// HealthAPI 返回健康检查服务
+func (c *Client) HealthAPI() HealthAPI { return c.Health }
@@ -0,0 +1,231 @@
+package sdk
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// BulkUploadOptions 批量上传选项
+type BulkUploadOptions struct {
+	// 并发上传数，默认 4
+	Concurrency int
+
+	// 每秒最多发起的上传数，0 表示不限制
+	RateLimit float64
+
+	// 是否在上传完成后等待文档处理结束
+	WaitForProcessing bool
+
+	// 等待处理结束的轮询选项，仅在 WaitForProcessing 为 true 时有效
+	WaitOptions *WaitOptions
+
+	// 每个文档上传完成（或处理结束）后调用，调用是串行的，可以在这里关闭文件
+	OnResult func(result *BulkUploadResult)
+}
+
+// BulkUploadResult 单个文档的上传结果
+type BulkUploadResult struct {
+	// 请求在输入中的序号，从 0 开始
+	Index int
+
+	Request  *UploadDocumentRequest
+	Response *UploadDocumentResponse
+
+	// 处理结束后的文档，仅在 WaitForProcessing 为 true 且上传成功时有值
+	Document *Document
+
+	// 上传失败或处理失败（*DocumentFailedError）时的错误
+	Err error
+}
+
+// BulkUploadReport 批量上传汇总
+type BulkUploadReport struct {
+	// 按 Index 排序的结果，取消时不包含仍在 reqs 中未被读取的请求
+	Results   []BulkUploadResult
+	Succeeded int
+	Failed    int
+	Duration  time.Duration
+}
+
+// Failures 返回失败的结果
+func (r *BulkUploadReport) Failures() []BulkUploadResult {
+	var failures []BulkUploadResult
+	for _, result := range r.Results {
+		if result.Err != nil {
+			failures = append(failures, result)
+		}
+	}
+	return failures
+}
+
+// UploadMany 使用固定数量的 worker 批量上传文档
+//
+// 从 reqs 读取请求直到 channel 关闭，单个文档失败不会中断其他上传，结果汇总在返回的报告中。
+// ctx 结束时停止读取新请求并中断正在进行的上传，返回已完成部分的报告和 ctx 的错误。
+//
+// callOpts 作用于每一个上传和等待处理的请求：设置了幂等键时每个上传使用 "键-序号" 作为幂等键，
+// WithResponseMeta 记录最后一个完成的请求。
+func (s *DocumentsService) UploadMany(ctx context.Context, reqs <-chan *UploadDocumentRequest, opts *BulkUploadOptions, callOpts ...CallOption) (*BulkUploadReport, error) {
+	if opts == nil {
+		opts = &BulkUploadOptions{}
+	}
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+
+	var limiter *rateLimiter
+	if opts.RateLimit > 0 {
+		limiter = newRateLimiter(opts.RateLimit, 1)
+	}
+
+	// 上传并发执行，每个上传先写入自己的 meta，完成时再复制到调用方的 meta
+	respMeta := responseMetaOf(callOpts)
+
+	start := time.Now()
+	var (
+		mu      sync.Mutex
+		results []BulkUploadResult
+	)
+	collect := func(result BulkUploadResult, meta *ResponseMeta) {
+		mu.Lock()
+		defer mu.Unlock()
+		if respMeta != nil && meta != nil {
+			*respMeta = *meta
+		}
+		results = append(results, result)
+		if opts.OnResult != nil && !opts.WaitForProcessing {
+			opts.OnResult(&result)
+		}
+	}
+
+	type job struct {
+		index int
+		req   *UploadDocumentRequest
+		opts  []CallOption
+		meta  *ResponseMeta
+	}
+	jobs := make(chan job)
+
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				resp, err := s.Upload(ctx, j.req, j.opts...)
+				collect(BulkUploadResult{Index: j.index, Request: j.req, Response: resp, Err: err}, j.meta)
+			}
+		}()
+	}
+
+	// 分发请求
+	var ctxErr error
+	index := 0
+dispatch:
+	for {
+		select {
+		case <-ctx.Done():
+			ctxErr = ctx.Err()
+			break dispatch
+		case req, ok := <-reqs:
+			if !ok {
+				break dispatch
+			}
+			// 已经读取但没有交给 worker 的请求记为失败，调用方可以在 OnResult 中关闭文件
+			if limiter != nil {
+				if _, err := limiter.wait(ctx); err != nil {
+					ctxErr = err
+					collect(BulkUploadResult{Index: index, Request: req, Err: err}, nil)
+					break dispatch
+				}
+			}
+			j := job{index: index, req: req, opts: requestCallOptions(callOpts, strconv.Itoa(index))}
+			if respMeta != nil {
+				j.meta = &ResponseMeta{}
+				j.opts = append(j.opts[:len(j.opts):len(j.opts)], WithResponseMeta(j.meta))
+			}
+			select {
+			case jobs <- j:
+				index++
+			case <-ctx.Done():
+				ctxErr = ctx.Err()
+				collect(BulkUploadResult{Index: index, Request: req, Err: ctxErr}, nil)
+				break dispatch
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+
+	if opts.WaitForProcessing && ctxErr == nil {
+		ctxErr = s.waitBulk(ctx, results, opts.WaitOptions, callOpts)
+	}
+	if opts.WaitForProcessing && opts.OnResult != nil {
+		for i := range results {
+			opts.OnResult(&results[i])
+		}
+	}
+
+	report := &BulkUploadReport{
+		Results:  results,
+		Duration: time.Since(start),
+	}
+	for _, result := range results {
+		if result.Err != nil {
+			report.Failed++
+		} else {
+			report.Succeeded++
+		}
+	}
+	return report, ctxErr
+}
+
+// waitBulk 按数据集分组等待上传成功的文档处理结束，并将结果写回 results
+func (s *DocumentsService) waitBulk(ctx context.Context, results []BulkUploadResult, opts *WaitOptions, callOpts []CallOption) error {
+	groups := make(map[string][]int)
+	var datasetIDs []string
+	for i, result := range results {
+		if result.Err != nil || result.Response == nil {
+			continue
+		}
+		datasetID := result.Request.DatasetID
+		if _, ok := groups[datasetID]; !ok {
+			datasetIDs = append(datasetIDs, datasetID)
+		}
+		groups[datasetID] = append(groups[datasetID], i)
+	}
+
+	for _, datasetID := range datasetIDs {
+		indexes := groups[datasetID]
+		documentIDs := make([]string, len(indexes))
+		for i, idx := range indexes {
+			documentIDs[i] = results[idx].Response.DocumentID
+		}
+
+		docs, err := s.WaitUntilAllProcessed(ctx, datasetID, documentIDs, opts, callOpts...)
+		if docs == nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			for _, idx := range indexes {
+				results[idx].Err = err
+			}
+			continue
+		}
+
+		for i, idx := range indexes {
+			doc := docs[i]
+			results[idx].Document = &doc
+			if doc.Status == DocumentStatusFailed {
+				results[idx].Err = &DocumentFailedError{Document: doc}
+			}
+		}
+	}
+	return nil
+}
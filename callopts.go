@@ -0,0 +1,100 @@
+package sdk
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// headerIdempotencyKey 幂等键请求头，服务端据此对重复的请求只处理一次
+const headerIdempotencyKey = "Idempotency-Key"
+
+// CallOption 单次调用的配置选项，只作用于当前请求，在中间件之前应用到 Operation
+type CallOption func(*Operation)
+
+// WithCallTimeout 设置本次调用的超时时间，包括重试和排队；流式调用在 Stream 关闭前一直有效
+//
+// 超时时间不能超过客户端的 WithTimeout，两者同时生效。
+func WithCallTimeout(timeout time.Duration) CallOption {
+	return func(op *Operation) {
+		op.Timeout = timeout
+	}
+}
+
+// WithHeader 为本次调用添加请求头，覆盖同名的请求头
+func WithHeader(key, value string) CallOption {
+	return func(op *Operation) {
+		op.SetHeader(key, value)
+	}
+}
+
+// WithIdempotencyKey 设置 Idempotency-Key 请求头，key 为空时每次调用生成新的随机值
+//
+// 带有幂等键的请求按幂等请求处理：Datasets.Create、Documents.Upload 等非幂等请求
+// 在连接失败或返回 5xx 时也会重试，并可以切换到其他服务地址，所有尝试使用同一个幂等键。
+func WithIdempotencyKey(key string) CallOption {
+	return func(op *Operation) {
+		if key == "" {
+			op.SetHeader(headerIdempotencyKey, randomID())
+			return
+		}
+		op.SetHeader(headerIdempotencyKey, key)
+	}
+}
+
+// WithRequestID 设置本次调用的 X-Request-Id 请求头，用于关联客户端和服务端的日志；
+// 未设置时使用 ContextWithRequestID 中的请求 ID 或随机生成
+func WithRequestID(id string) CallOption {
+	return WithHeader(headerRequestID, id)
+}
+
+// WithResponseMeta 在调用结束后将状态码、响应头、请求 ID、耗时和重试次数写入 meta
+func WithResponseMeta(meta *ResponseMeta) CallOption {
+	return func(op *Operation) {
+		op.meta = meta
+	}
+}
+
+// apply 应用单次调用的选项
+func (op *Operation) apply(opts []CallOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// requestCallOptions 返回批量操作中单个请求的调用选项
+//
+// opts 设置了幂等键时，该请求使用 "键-suffix" 作为幂等键，避免批量操作中的不同请求被服务端当作重复请求，
+// 同时重新执行同一个批量操作时，每个请求的幂等键保持不变。
+func requestCallOptions(opts []CallOption, suffix string) []CallOption {
+	var op Operation
+	op.apply(opts)
+	key := op.Header.Get(headerIdempotencyKey)
+	if key == "" {
+		return opts
+	}
+	return append(opts[:len(opts):len(opts)], WithIdempotencyKey(key+"-"+suffix))
+}
+
+// responseMetaOf 返回 opts 中 WithResponseMeta 设置的 meta，没有时返回 nil
+func responseMetaOf(opts []CallOption) *ResponseMeta {
+	var op Operation
+	op.apply(opts)
+	return op.meta
+}
+
+// callContext 按 op.Timeout 为本次调用设置超时
+func callContext(ctx context.Context, op *Operation) (context.Context, context.CancelFunc) {
+	if op.Timeout > 0 {
+		return context.WithTimeout(ctx, op.Timeout)
+	}
+	return ctx, func() {}
+}
+
+// randomID 生成随机的 32 位十六进制字符串，用作幂等键和请求 ID
+func randomID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,139 @@
+// Package cassette 录制和回放 HTTP 交互，用于编写不依赖真实服务的确定性集成测试。
+//
+// 首次运行时以录制模式连接真实的 RAGLite 服务，请求和响应会在脱敏后写入 cassette 文件；
+// 之后在 CI 中以回放模式运行，按方法、路径、查询参数和规范化后的请求体匹配录制的交互：
+//
+//	rec, err := cassette.New("testdata/search.json", cassette.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Close()
+//
+//	client, _ := sdk.NewClient(baseURL, sdk.WithTransport(rec), sdk.WithAPIKey(apiKey))
+package cassette
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"unicode/utf8"
+)
+
+// formatVersion cassette 文件格式版本
+const formatVersion = 1
+
+// Cassette 录制的交互列表
+type Cassette struct {
+	Version      int            `json:"version"`
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction 一次请求和对应的响应
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request 录制的请求
+type Request struct {
+	Method string      `json:"method"`
+	Path   string      `json:"path"`
+	Query  string      `json:"query,omitempty"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Response 录制的响应
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       Body        `json:"body,omitempty"`
+}
+
+// Body 请求或响应体
+//
+// 写入文件时，JSON 内容原样嵌入以便阅读和修改，其他 UTF-8 文本保存为字符串，二进制内容使用 base64。
+type Body []byte
+
+// bodyJSON Body 在文件中的表示
+type bodyJSON struct {
+	JSON   json.RawMessage `json:"json,omitempty"`
+	Text   *string         `json:"text,omitempty"`
+	Base64 string          `json:"base64,omitempty"`
+}
+
+// MarshalJSON 实现 json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	switch {
+	case len(b) == 0:
+		return []byte("null"), nil
+	case json.Valid(b):
+		return json.Marshal(bodyJSON{JSON: json.RawMessage(b)})
+	case utf8.Valid(b):
+		text := string(b)
+		return json.Marshal(bodyJSON{Text: &text})
+	default:
+		return json.Marshal(bodyJSON{Base64: base64.StdEncoding.EncodeToString(b)})
+	}
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*b = nil
+		return nil
+	}
+
+	var v bodyJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	switch {
+	case v.JSON != nil:
+		*b = Body(v.JSON)
+	case v.Text != nil:
+		*b = Body(*v.Text)
+	case v.Base64 != "":
+		raw, err := base64.StdEncoding.DecodeString(v.Base64)
+		if err != nil {
+			return fmt.Errorf("failed to decode base64 body: %w", err)
+		}
+		*b = raw
+	default:
+		*b = nil
+	}
+	return nil
+}
+
+// Load 读取 cassette 文件
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cassette %s: %w", path, err)
+	}
+	if c.Version != formatVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d in %s", c.Version, path)
+	}
+	return &c, nil
+}
+
+// Save 写入 cassette 文件，目录不存在时自动创建
+func (c *Cassette) Save(path string) error {
+	c.Version = formatVersion
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal cassette: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
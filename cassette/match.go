@@ -0,0 +1,209 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MismatchError 回放时没有录制的交互与请求匹配
+type MismatchError struct {
+	// 实际的请求（已脱敏）
+	Request *Request
+
+	// 最接近的录制请求，cassette 为空时为 nil
+	Closest *Request
+
+	// 实际请求与最接近的录制请求之间的差异
+	Diff string
+}
+
+// Error 实现 error 接口
+func (e *MismatchError) Error() string {
+	msg := fmt.Sprintf("cassette: no recorded interaction matches %s %s", e.Request.Method, requestURI(e.Request))
+	if e.Closest == nil {
+		return msg + " (cassette has no unused interactions)"
+	}
+	return fmt.Sprintf("%s\nclosest recorded request %s %s differs (- recorded, + actual):\n%s",
+		msg, e.Closest.Method, requestURI(e.Closest), e.Diff)
+}
+
+func requestURI(r *Request) string {
+	if r.Query == "" {
+		return r.Path
+	}
+	return r.Path + "?" + r.Query
+}
+
+// canonicalRequest 用于匹配和比较的规范化请求
+type canonicalRequest struct {
+	method string
+	path   string
+	query  string
+	body   string
+}
+
+func canonicalize(r *Request) canonicalRequest {
+	return canonicalRequest{
+		method: r.Method,
+		path:   r.Path,
+		query:  canonicalQuery(r.Query),
+		body:   canonicalBody(r.Body, r.Header.Get("Content-Type")),
+	}
+}
+
+// canonicalQuery 按参数名排序，忽略参数顺序
+func canonicalQuery(query string) string {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return query
+	}
+	for _, v := range values {
+		sort.Strings(v)
+	}
+	return values.Encode()
+}
+
+// canonicalBody 将请求体转换为便于比较的文本：
+// JSON 按键排序并缩进，multipart 表单忽略随机的 boundary，其他内容原样比较
+func canonicalBody(body Body, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if json.Valid(body) {
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		var v interface{}
+		if err := dec.Decode(&v); err == nil {
+			if data, err := json.MarshalIndent(v, "", "  "); err == nil {
+				return string(data)
+			}
+		}
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err == nil && mediaType == "multipart/form-data" {
+		if form, ok := canonicalMultipart(body, params["boundary"]); ok {
+			return form
+		}
+	}
+	return string(body)
+}
+
+// canonicalMultipart 将 multipart 表单转换为按字段排列的文本
+func canonicalMultipart(body Body, boundary string) (string, bool) {
+	if boundary == "" {
+		return "", false
+	}
+
+	var parts []string
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", false
+		}
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return "", false
+		}
+
+		header := fmt.Sprintf("--- %s", part.FormName())
+		if name := part.FileName(); name != "" {
+			header += fmt.Sprintf(" (file %q)", name)
+		}
+		parts = append(parts, header+"\n"+canonicalBody(content, part.Header.Get("Content-Type")))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "\n"), true
+}
+
+// similarity 用于在没有匹配时挑选最接近的录制请求
+func (c canonicalRequest) similarity(other canonicalRequest) int {
+	score := 0
+	if c.method == other.method {
+		score += 8
+	}
+	if c.path == other.path {
+		score += 4
+	}
+	if c.query == other.query {
+		score += 2
+	}
+	if c.body == other.body {
+		score++
+	}
+	return score
+}
+
+// diff 列出两个规范化请求的差异
+func (c canonicalRequest) diff(actual canonicalRequest) string {
+	var b strings.Builder
+	field := func(name, recorded, actual string) {
+		if recorded == actual {
+			return
+		}
+		fmt.Fprintf(&b, "%s:\n", name)
+		b.WriteString(diffLines(recorded, actual))
+	}
+	field("method", c.method, actual.method)
+	field("path", c.path, actual.path)
+	field("query", strings.ReplaceAll(c.query, "&", "\n"), strings.ReplaceAll(actual.query, "&", "\n"))
+	field("body", c.body, actual.body)
+	return b.String()
+}
+
+// diffLines 基于最长公共子序列的逐行差异，删除和新增的行分别以 - 和 + 标记
+func diffLines(a, b string) string {
+	x, y := splitLines(a), splitLines(b)
+
+	// lcs[i][j] 为 x[i:] 和 y[j:] 的最长公共子序列长度
+	lcs := make([][]int, len(x)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(y)+1)
+	}
+	for i := len(x) - 1; i >= 0; i-- {
+		for j := len(y) - 1; j >= 0; j-- {
+			if x[i] == y[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(x) || j < len(y) {
+		switch {
+		case i < len(x) && j < len(y) && x[i] == y[j]:
+			fmt.Fprintf(&out, "    %s\n", x[i])
+			i++
+			j++
+		case i < len(x) && (j == len(y) || lcs[i+1][j] >= lcs[i][j+1]):
+			fmt.Fprintf(&out, "  - %s\n", x[i])
+			i++
+		default:
+			fmt.Fprintf(&out, "  + %s\n", y[j])
+			j++
+		}
+	}
+	return out.String()
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
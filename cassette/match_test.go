@@ -0,0 +1,153 @@
+package cassette
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// multipartBody 构造包含一个普通字段和一个文件的表单，boundary 由 multipart.Writer 随机生成
+func multipartBody(t *testing.T, title, content string) (Body, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if err := w.WriteField("title", title); err != nil {
+		t.Fatal(err)
+	}
+	fw, err := w.CreateFormFile("file", "a.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fw.Write([]byte(content))
+	w.Close()
+	return buf.Bytes(), w.FormDataContentType()
+}
+
+func jsonRequest(method, path, query, body string) *Request {
+	return &Request{
+		Method: method,
+		Path:   path,
+		Query:  query,
+		Header: http.Header{"Content-Type": {"application/json"}},
+		Body:   Body(body),
+	}
+}
+
+func TestCanonicalizeMatching(t *testing.T) {
+	form1, contentType1 := multipartBody(t, "doc", "hello")
+	form2, contentType2 := multipartBody(t, "doc", "hello")
+	form3, contentType3 := multipartBody(t, "doc", "changed")
+
+	tests := []struct {
+		name      string
+		recorded  *Request
+		actual    *Request
+		wantMatch bool
+	}{
+		{
+			name:      "identical",
+			recorded:  jsonRequest("POST", "/api/v1/search", "", `{"query":"q"}`),
+			actual:    jsonRequest("POST", "/api/v1/search", "", `{"query":"q"}`),
+			wantMatch: true,
+		},
+		{
+			name:      "query parameter order is ignored",
+			recorded:  jsonRequest("GET", "/api/v1/datasets", "page=1&page_size=20", ""),
+			actual:    jsonRequest("GET", "/api/v1/datasets", "page_size=20&page=1", ""),
+			wantMatch: true,
+		},
+		{
+			name:      "JSON key order and whitespace are ignored",
+			recorded:  jsonRequest("POST", "/api/v1/search", "", `{"query":"q","top_k":10}`),
+			actual:    jsonRequest("POST", "/api/v1/search", "", "{\n  \"top_k\": 10,\n  \"query\": \"q\"\n}"),
+			wantMatch: true,
+		},
+		{
+			name:      "large JSON numbers keep their precision",
+			recorded:  jsonRequest("POST", "/api/v1/search", "", `{"n":9007199254740993}`),
+			actual:    jsonRequest("POST", "/api/v1/search", "", `{"n":9007199254740992}`),
+			wantMatch: false,
+		},
+		{
+			name:      "different method",
+			recorded:  jsonRequest("GET", "/api/v1/datasets/ds-1", "", ""),
+			actual:    jsonRequest("DELETE", "/api/v1/datasets/ds-1", "", ""),
+			wantMatch: false,
+		},
+		{
+			name:      "different query value",
+			recorded:  jsonRequest("GET", "/api/v1/datasets", "page=1", ""),
+			actual:    jsonRequest("GET", "/api/v1/datasets", "page=2", ""),
+			wantMatch: false,
+		},
+		{
+			name:      "multipart boundary is ignored",
+			recorded:  &Request{Method: "POST", Path: "/upload", Header: http.Header{"Content-Type": {contentType1}}, Body: form1},
+			actual:    &Request{Method: "POST", Path: "/upload", Header: http.Header{"Content-Type": {contentType2}}, Body: form2},
+			wantMatch: true,
+		},
+		{
+			name:      "multipart file content is compared",
+			recorded:  &Request{Method: "POST", Path: "/upload", Header: http.Header{"Content-Type": {contentType1}}, Body: form1},
+			actual:    &Request{Method: "POST", Path: "/upload", Header: http.Header{"Content-Type": {contentType3}}, Body: form3},
+			wantMatch: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalize(tt.recorded) == canonicalize(tt.actual); got != tt.wantMatch {
+				t.Errorf("match = %v, want %v", got, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func TestCanonicalRequestDiff(t *testing.T) {
+	recorded := canonicalize(jsonRequest("POST", "/api/v1/search", "top_k=5", `{"query":"old","dataset_id":"ds-1"}`))
+	actual := canonicalize(jsonRequest("POST", "/api/v1/search", "top_k=5", `{"query":"new","dataset_id":"ds-1"}`))
+
+	diff := recorded.diff(actual)
+	for _, want := range []string{"body:\n", `  -   "query": "old"`, `  +   "query": "new"`, `      "dataset_id": "ds-1",`} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff does not contain %q:\n%s", want, diff)
+		}
+	}
+	for _, unwanted := range []string{"method:", "path:", "query:\n"} {
+		if strings.Contains(diff, unwanted) {
+			t.Errorf("diff contains unchanged field %q:\n%s", unwanted, diff)
+		}
+	}
+}
+
+func TestDiffLines(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{"equal", "a\nb", "a\nb", "    a\n    b\n"},
+		{"changed line", "a\nb\nc", "a\nx\nc", "    a\n  - b\n  + x\n    c\n"},
+		{"added line", "a\nc", "a\nb\nc", "    a\n  + b\n    c\n"},
+		{"removed line", "a\nb\nc", "a\nc", "    a\n  - b\n    c\n"},
+		{"from empty", "", "a", "  + a\n"},
+		{"to empty", "a", "", "  - a\n"},
+	}
+	for _, tt := range tests {
+		if got := diffLines(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s: diffLines(%q, %q) =\n%s\nwant\n%s", tt.name, tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarityPrefersMethodAndPath(t *testing.T) {
+	actual := canonicalize(jsonRequest("POST", "/api/v1/search", "", `{"query":"q"}`))
+	samePath := canonicalize(jsonRequest("POST", "/api/v1/search", "", `{"query":"other"}`))
+	sameBody := canonicalize(jsonRequest("POST", "/api/v1/qa", "", `{"query":"q"}`))
+
+	if samePath.similarity(actual) <= sameBody.similarity(actual) {
+		t.Errorf("similarity of same path (%d) should exceed same body (%d)",
+			samePath.similarity(actual), sameBody.similarity(actual))
+	}
+}
@@ -0,0 +1,264 @@
+package cassette
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mode 录制或回放模式
+type Mode int
+
+const (
+	// ModeReplay 只回放，cassette 文件必须存在，不会访问网络
+	ModeReplay Mode = iota
+
+	// ModeRecord 总是请求真实服务，Close 时覆盖 cassette 文件
+	ModeRecord
+
+	// ModeAuto cassette 文件存在时回放，否则录制
+	ModeAuto
+)
+
+// String 实现 fmt.Stringer
+func (m Mode) String() string {
+	switch m {
+	case ModeReplay:
+		return "replay"
+	case ModeRecord:
+		return "record"
+	case ModeAuto:
+		return "auto"
+	default:
+		return "Mode(" + strconv.Itoa(int(m)) + ")"
+	}
+}
+
+// Option Recorder 配置选项
+type Option func(*Recorder)
+
+// WithRealTransport 设置录制时实际发送请求的 Transport，默认为 http.DefaultTransport
+func WithRealTransport(transport http.RoundTripper) Option {
+	return func(r *Recorder) {
+		r.transport = transport
+	}
+}
+
+// WithSensitiveHeaders 追加需要脱敏的请求头和响应头
+func WithSensitiveHeaders(names ...string) Option {
+	return func(r *Recorder) {
+		r.scrubber.headers = append(r.scrubber.headers, names...)
+	}
+}
+
+// WithSensitiveFields 追加需要脱敏的 JSON 字段和查询参数名，不区分大小写
+func WithSensitiveFields(names ...string) Option {
+	return func(r *Recorder) {
+		for _, name := range names {
+			r.scrubber.fields[strings.ToLower(name)] = true
+		}
+	}
+}
+
+// WithScrubber 添加自定义脱敏函数，在内置脱敏之后执行
+//
+// 录制时函数作用于完整的交互；回放时只有请求部分有效，用于让实际请求与录制内容保持一致。
+func WithScrubber(fn func(*Interaction)) Option {
+	return func(r *Recorder) {
+		r.scrubber.custom = append(r.scrubber.custom, fn)
+	}
+}
+
+// Recorder 录制或回放交互的 http.RoundTripper，通过 sdk.WithTransport 接入客户端
+//
+// 回放时按顺序使用未被使用过的匹配交互；匹配的交互都已用过时重复使用最后一个，
+// 以便轮询次数与录制时不同的场景（例如 WaitUntilProcessed）也能回放。
+type Recorder struct {
+	path      string
+	mode      Mode
+	transport http.RoundTripper
+	scrubber  *scrubber
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+// New 创建 Recorder，录制模式下需要调用 Close 保存 cassette 文件
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	r := &Recorder{
+		path:      path,
+		mode:      mode,
+		transport: http.DefaultTransport,
+		scrubber:  newScrubber(),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	c, err := Load(path)
+	switch {
+	case err == nil && r.mode == ModeAuto:
+		r.mode = ModeReplay
+	case errors.Is(err, fs.ErrNotExist) && r.mode == ModeAuto:
+		r.mode = ModeRecord
+	}
+
+	switch {
+	case r.mode == ModeRecord:
+		r.cassette = &Cassette{Version: formatVersion}
+	case err != nil:
+		return nil, fmt.Errorf("failed to load cassette: %w", err)
+	default:
+		r.cassette = c
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// Mode 返回实际使用的模式，ModeAuto 会解析为 ModeReplay 或 ModeRecord
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Close 录制模式下保存 cassette 文件
+func (r *Recorder) Close() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.path)
+}
+
+// RoundTrip 实现 http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read request body: %w", err)
+	}
+	recorded := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Header: req.Header.Clone(),
+		Body:   body,
+	}
+
+	if r.mode == ModeRecord {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+// record 发送请求并保存脱敏后的交互
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := readBody(&resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	i := &Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			Body:       append(Body(nil), body...),
+		},
+	}
+	r.scrubber.scrub(i)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, i)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay 查找匹配的交互并构造响应
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.scrubber.scrubRequest(&recorded)
+	for _, fn := range r.scrubber.custom {
+		i := &Interaction{Request: recorded}
+		fn(i)
+		recorded = i.Request
+	}
+	actual := canonicalize(&recorded)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	match, last, closest, best := -1, -1, -1, -1
+	for idx, i := range r.cassette.Interactions {
+		c := canonicalize(&i.Request)
+		if c == actual {
+			if !r.used[idx] {
+				match = idx
+				break
+			}
+			last = idx
+			continue
+		}
+		if r.used[idx] {
+			continue
+		}
+		if score := c.similarity(actual); score > best {
+			closest, best = idx, score
+		}
+	}
+	if match < 0 {
+		match = last
+	}
+
+	if match < 0 {
+		mismatch := &MismatchError{Request: &recorded}
+		if closest >= 0 {
+			mismatch.Closest = &r.cassette.Interactions[closest].Request
+			mismatch.Diff = canonicalize(mismatch.Closest).diff(actual)
+		}
+		return nil, mismatch
+	}
+
+	r.used[match] = true
+	recordedResp := r.cassette.Interactions[match].Response
+	header := recordedResp.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	// 录制文件中的 JSON 可能被重新缩进，按实际长度设置
+	header.Set("Content-Length", strconv.Itoa(len(recordedResp.Body)))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recordedResp.StatusCode, http.StatusText(recordedResp.StatusCode)),
+		StatusCode:    recordedResp.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(recordedResp.Body)),
+		ContentLength: int64(len(recordedResp.Body)),
+		Request:       req,
+	}, nil
+}
+
+// readBody 读取并替换 body，使其可以再次读取
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	if err != nil {
+		return nil, err
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, nil
+}
@@ -0,0 +1,216 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// doRequest 通过 rt 发送请求并返回状态码和响应体
+func doRequest(t *testing.T, rt http.RoundTripper, method, url, body string) (int, string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret-key")
+
+	resp, err := (&http.Client{Transport: rt}).Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 卡带以缩进格式保存 JSON 响应体，比较前统一压缩
+	var compact bytes.Buffer
+	if json.Compact(&compact, data) == nil {
+		data = compact.Bytes()
+	}
+	return resp.StatusCode, string(data), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	var served atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := served.Add(1)
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		switch {
+		case r.URL.Path == "/api/v1/models":
+			// 脱敏后的 JSON 按键排序重新编码，这里直接使用排序后的键
+			io.WriteString(w, `{"data":{"api_key":"sk-live","id":"m-1"},"success":true}`)
+		case bytes.Contains(body, []byte(`"status"`)):
+			io.WriteString(w, `{"success":true,"data":{"poll":`+string('0'+rune(n))+`}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"success":false,"message":"not found"}`)
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "testdata", "cassette.json")
+
+	// 录制
+	rec, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != ModeRecord {
+		t.Fatalf("Mode() = %s, want record when the cassette does not exist", rec.Mode())
+	}
+	type result struct {
+		status int
+		body   string
+	}
+	requests := []struct{ method, path, body string }{
+		{"POST", "/api/v1/models", `{"name":"m","api_key":"sk-live"}`},
+		{"POST", "/api/v1/poll", `{"status":true}`},
+		{"POST", "/api/v1/poll", `{"status":true}`},
+		{"GET", "/api/v1/missing", ""},
+	}
+	var recorded []result
+	for _, r := range requests {
+		status, body, err := doRequest(t, rec, r.method, srv.URL+r.path, r.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		recorded = append(recorded, result{status, body})
+	}
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// 文件中不包含凭据
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret-key", "sk-live", "session=abc"} {
+		if bytes.Contains(data, []byte(secret)) {
+			t.Errorf("cassette file contains %q", secret)
+		}
+	}
+
+	// 回放，服务已关闭，请求体的键顺序不同
+	srv.Close()
+	replay, err := New(path, ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replay.Mode() != ModeReplay {
+		t.Fatalf("Mode() = %s, want replay when the cassette exists", replay.Mode())
+	}
+	var replayed []result
+	for _, r := range requests {
+		body := r.body
+		if r.path == "/api/v1/models" {
+			body = `{"api_key":"sk-other", "name":"m"}`
+		}
+		status, respBody, err := doRequest(t, replay, r.method, srv.URL+r.path, body)
+		if err != nil {
+			t.Fatalf("replay %s %s: %v", r.method, r.path, err)
+		}
+		replayed = append(replayed, result{status, respBody})
+	}
+
+	// 响应中的凭据已被脱敏，其他内容与录制时一致
+	recorded[0].body = strings.Replace(recorded[0].body, "sk-live", Redacted, 1)
+	if !reflect.DeepEqual(replayed, recorded) {
+		t.Errorf("replayed = %+v, want %+v", replayed, recorded)
+	}
+
+	// 匹配的交互用完后重复使用最后一个
+	_, body, err := doRequest(t, replay, "POST", srv.URL+"/api/v1/poll", `{"status":true}`)
+	if err != nil || body != recorded[2].body {
+		t.Errorf("extra poll = %q, %v, want the last recorded poll %q", body, err, recorded[2].body)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	c := &Cassette{Interactions: []*Interaction{
+		{
+			Request:  *jsonRequest("POST", "/api/v1/search", "", `{"query":"recorded"}`),
+			Response: Response{StatusCode: 200, Body: Body(`{"success":true}`)},
+		},
+		{
+			Request:  *jsonRequest("GET", "/api/v1/datasets", "", ""),
+			Response: Response{StatusCode: 200, Body: Body(`{"success":true}`)},
+		},
+	}}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = doRequest(t, rec, "POST", "http://raglite.test/api/v1/search", `{"query":"actual"}`)
+
+	var mismatch *MismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("err = %v, want *MismatchError", err)
+	}
+	if mismatch.Closest == nil || mismatch.Closest.Path != "/api/v1/search" {
+		t.Fatalf("Closest = %+v, want the recorded search request", mismatch.Closest)
+	}
+	for _, want := range []string{`  -   "query": "recorded"`, `  +   "query": "actual"`} {
+		if !strings.Contains(mismatch.Error(), want) {
+			t.Errorf("error does not contain %q:\n%s", want, mismatch.Error())
+		}
+	}
+}
+
+func TestReplayRequiresCassette(t *testing.T) {
+	_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("New() error = %v, want a not-exist error in replay mode", err)
+	}
+}
+
+func TestBodyRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     Body
+		wantKind string
+	}{
+		{"json", Body(`{"a":1}`), `"json"`},
+		{"text", Body("plain text"), `"text"`},
+		{"binary", Body([]byte{0xff, 0x00, 0xfe}), `"base64"`},
+		{"empty", nil, "null"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.body.MarshalJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(data), tt.wantKind) {
+				t.Errorf("MarshalJSON() = %s, want %s encoding", data, tt.wantKind)
+			}
+
+			var got Body
+			if err := got.UnmarshalJSON(data); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tt.body) {
+				t.Errorf("round trip = %q, want %q", got, tt.body)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// Redacted 替换敏感信息的占位符
+const Redacted = "[REDACTED]"
+
+// DefaultSensitiveHeaders 默认脱敏的请求头和响应头，与 SDK 日志脱敏使用同一份列表（sdk.SensitiveHeaders）
+var DefaultSensitiveHeaders = append([]string(nil), sdk.SensitiveHeaders...)
+
+// DefaultSensitiveFields 默认脱敏的 JSON 字段和查询参数，按名称匹配且不区分大小写，
+// 与 SDK 日志脱敏使用同一份列表（sdk.SensitiveFields），覆盖 AIModelConfig.APIKey、CustomHeaders 等凭据
+var DefaultSensitiveFields = append([]string(nil), sdk.SensitiveFields...)
+
+// scrubber 按配置脱敏交互
+type scrubber struct {
+	headers []string
+	fields  map[string]bool
+	custom  []func(*Interaction)
+}
+
+func newScrubber() *scrubber {
+	s := &scrubber{
+		headers: append([]string(nil), DefaultSensitiveHeaders...),
+		fields:  make(map[string]bool),
+	}
+	for _, f := range DefaultSensitiveFields {
+		s.fields[f] = true
+	}
+	return s
+}
+
+// scrub 脱敏交互，录制时作用于请求和响应，回放时只作用于请求以便与录制内容比较
+func (s *scrubber) scrub(i *Interaction) {
+	s.scrubRequest(&i.Request)
+
+	for _, name := range s.headers {
+		if i.Response.Header.Get(name) != "" {
+			i.Response.Header.Set(name, Redacted)
+		}
+	}
+	i.Response.Body = s.scrubBody(i.Response.Body)
+
+	for _, fn := range s.custom {
+		fn(i)
+	}
+}
+
+func (s *scrubber) scrubRequest(r *Request) {
+	for _, name := range s.headers {
+		if r.Header.Get(name) != "" {
+			r.Header.Set(name, Redacted)
+		}
+	}
+
+	if r.Query != "" {
+		if values, err := url.ParseQuery(r.Query); err == nil {
+			changed := false
+			for key := range values {
+				if s.fields[strings.ToLower(key)] {
+					values[key] = []string{Redacted}
+					changed = true
+				}
+			}
+			if changed {
+				r.Query = values.Encode()
+			}
+		}
+	}
+
+	r.Body = s.scrubBody(r.Body)
+}
+
+// scrubBody 脱敏 JSON 请求体中的敏感字段，其他内容原样返回
+func (s *scrubber) scrubBody(body Body) Body {
+	if len(body) == 0 || !json.Valid(body) {
+		return body
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !s.scrubValue(v) {
+		return body
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return data
+}
+
+// scrubValue 递归替换敏感字段，返回是否有修改
+func (s *scrubber) scrubValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if s.fields[strings.ToLower(key)] {
+				if value, ok := scrubField(child); ok {
+					v[key] = value
+					changed = true
+				}
+				continue
+			}
+			changed = s.scrubValue(child) || changed
+		}
+	case []interface{}:
+		for _, child := range v {
+			changed = s.scrubValue(child) || changed
+		}
+	}
+	return changed
+}
+
+// scrubField 返回敏感字段脱敏后的值：对象（如 custom_headers）保留键名、替换每个值，空字符串不需要脱敏
+func scrubField(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case string:
+		return Redacted, v != ""
+	case map[string]interface{}:
+		for name := range v {
+			v[name] = Redacted
+		}
+		return v, len(v) > 0
+	default:
+		return Redacted, true
+	}
+}
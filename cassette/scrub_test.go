@@ -0,0 +1,57 @@
+package cassette
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func TestDefaultsMatchSDKLogging(t *testing.T) {
+	if !reflect.DeepEqual(DefaultSensitiveHeaders, sdk.SensitiveHeaders) {
+		t.Errorf("DefaultSensitiveHeaders = %q, want sdk.SensitiveHeaders %q", DefaultSensitiveHeaders, sdk.SensitiveHeaders)
+	}
+	if !reflect.DeepEqual(DefaultSensitiveFields, sdk.SensitiveFields) {
+		t.Errorf("DefaultSensitiveFields = %q, want sdk.SensitiveFields %q", DefaultSensitiveFields, sdk.SensitiveFields)
+	}
+}
+
+func TestScrub(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "api_key",
+			in:   `{"name":"m","config":{"api_key":"sk-live"}}`,
+			want: `{"config":{"api_key":"[REDACTED]"},"name":"m"}`,
+		},
+		{
+			name: "custom_headers keeps names",
+			in:   `{"config":{"custom_headers":{"X-Tenant":"tenant-token","X-Trace":"t"}}}`,
+			want: `{"config":{"custom_headers":{"X-Tenant":"[REDACTED]","X-Trace":"[REDACTED]"}}}`,
+		},
+		{
+			name: "oauth2 token response",
+			in:   `{"access_token":"at","token_type":"Bearer","expires_in":3600}`,
+			want: `{"access_token":"[REDACTED]","expires_in":3600,"token_type":"Bearer"}`,
+		},
+		{
+			name: "empty values are kept",
+			in:   `{"api_key":"","custom_headers":{}}`,
+			want: `{"api_key":"","custom_headers":{}}`,
+		},
+	}
+	for _, tt := range tests {
+		i := &Interaction{
+			Request:  Request{Header: http.Header{}, Body: Body(tt.in)},
+			Response: Response{Header: http.Header{}, Body: Body(tt.in)},
+		}
+		newScrubber().scrub(i)
+		if string(i.Request.Body) != tt.want || string(i.Response.Body) != tt.want {
+			t.Errorf("%s: request = %s, response = %s, want %s", tt.name, i.Request.Body, i.Response.Body, tt.want)
+		}
+	}
+}
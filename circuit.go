@@ -0,0 +1,197 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState 熔断器状态
+type CircuitState int
+
+// 熔断器状态
+const (
+	CircuitClosed   CircuitState = iota // 正常发送请求
+	CircuitOpen                         // 直接返回 ErrCircuitOpen，不发送请求
+	CircuitHalfOpen                     // 正在通过健康检查探测服务是否恢复
+)
+
+// String 返回状态名称
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig 熔断器配置，零值字段使用默认值
+type CircuitBreakerConfig struct {
+	// 连续失败多少次后打开熔断器，默认 5。连接失败、超时和 5xx 响应计为失败
+	FailureThreshold int
+
+	// 打开后经过多久开始探测服务是否恢复，默认 30 秒
+	OpenTimeout time.Duration
+
+	// 探测直接请求服务地址的 /health，不经过中间件、重试和限流，超时时间默认 5 秒。
+	// 探测期间触发探测的请求会等待探测结束
+	ProbeTimeout time.Duration
+
+	// 状态变化时被调用，baseURL 为熔断器对应的服务地址。
+	// 回调在持有熔断器锁时同步执行，不能阻塞，也不能通过该客户端发送请求
+	OnStateChange func(baseURL string, from, to CircuitState)
+}
+
+// CircuitOpenError 熔断器打开期间请求被拒绝，没有发送到服务端
+//
+// errors.Is(err, ErrCircuitOpen) 为 true。
+type CircuitOpenError struct {
+	BaseURL string
+
+	// 预计开始探测恢复的时间
+	RetryAt time.Time
+}
+
+// Error 实现 error 接口
+func (e *CircuitOpenError) Error() string {
+	return "circuit breaker is open for " + e.BaseURL
+}
+
+// Is 支持 errors.Is(err, ErrCircuitOpen)
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// circuitBreaker 一个服务地址的熔断器
+type circuitBreaker struct {
+	baseURL string
+	config  CircuitBreakerConfig
+	probe   func(ctx context.Context) error
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// breaker 返回 baseURL 对应的熔断器，未配置熔断器时返回 nil
+func (c *Client) breaker(baseURL string) *circuitBreaker {
+	if c.circuitConfig == nil {
+		return nil
+	}
+
+	c.breakersMu.Lock()
+	defer c.breakersMu.Unlock()
+	if b := c.breakers[baseURL]; b != nil {
+		return b
+	}
+
+	config := *c.circuitConfig
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.ProbeTimeout <= 0 {
+		config.ProbeTimeout = 5 * time.Second
+	}
+	b := &circuitBreaker{
+		baseURL: baseURL,
+		config:  config,
+		probe: func(ctx context.Context) error {
+			return c.probeHealth(ctx, baseURL)
+		},
+	}
+	if c.breakers == nil {
+		c.breakers = make(map[string]*circuitBreaker)
+	}
+	c.breakers[baseURL] = b
+	return b
+}
+
+// allow 判断能否发送请求，熔断器打开时返回 *CircuitOpenError
+//
+// 打开时间超过 OpenTimeout 后，第一个到达的请求将熔断器切换为半开并同步执行健康检查，
+// 检查成功则关闭熔断器并继续发送该请求，失败则重新打开。探测期间其他请求直接失败。
+func (b *circuitBreaker) allow(ctx context.Context) error {
+	b.mu.Lock()
+	if b.state == CircuitClosed {
+		b.mu.Unlock()
+		return nil
+	}
+	if b.state == CircuitHalfOpen || time.Since(b.openedAt) < b.config.OpenTimeout {
+		err := b.openErrorLocked()
+		b.mu.Unlock()
+		return err
+	}
+	b.setStateLocked(CircuitHalfOpen)
+	b.mu.Unlock()
+
+	// 调用方取消不代表服务不可用，探测只受 ProbeTimeout 限制
+	probeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), b.config.ProbeTimeout)
+	probeErr := b.probe(probeCtx)
+	cancel()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probeErr != nil {
+		b.openLocked()
+		return b.openErrorLocked()
+	}
+	b.failures = 0
+	b.setStateLocked(CircuitClosed)
+	return nil
+}
+
+// record 记录一次请求的结果，只在关闭状态下统计连续失败次数
+func (b *circuitBreaker) record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != CircuitClosed {
+		return
+	}
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.config.FailureThreshold {
+		b.openLocked()
+	}
+}
+
+func (b *circuitBreaker) openLocked() {
+	b.openedAt = time.Now()
+	b.setStateLocked(CircuitOpen)
+}
+
+func (b *circuitBreaker) setStateLocked(state CircuitState) {
+	from := b.state
+	b.state = state
+	if from != state && b.config.OnStateChange != nil {
+		b.config.OnStateChange(b.baseURL, from, state)
+	}
+}
+
+func (b *circuitBreaker) openErrorLocked() error {
+	return &CircuitOpenError{BaseURL: b.baseURL, RetryAt: b.openedAt.Add(b.config.OpenTimeout)}
+}
+
+// isCircuitFailure 连接失败、超时和 5xx 响应说明服务可能不可用
+//
+// 调用方设置的截止时间到期同样计为失败，服务卡住时请求只会以超时结束；
+// 只有调用方主动取消（context.Canceled）的请求不计入。
+func isCircuitFailure(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(ctx.Err(), context.Canceled)
+	}
+	return resp.StatusCode >= 500
+}
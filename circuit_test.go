@@ -0,0 +1,298 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestBreaker 返回使用 probe 作为健康检查的熔断器，并记录状态变化
+func newTestBreaker(threshold int, openTimeout time.Duration, probe func(ctx context.Context) error) (*circuitBreaker, *[]string) {
+	var changes []string
+	return &circuitBreaker{
+		baseURL: "http://raglite.test",
+		config: CircuitBreakerConfig{
+			FailureThreshold: threshold,
+			OpenTimeout:      openTimeout,
+			ProbeTimeout:     time.Second,
+			OnStateChange: func(baseURL string, from, to CircuitState) {
+				changes = append(changes, fmt.Sprintf("%s: %s -> %s", baseURL, from, to))
+			},
+		},
+		probe: probe,
+	}, &changes
+}
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	errDown := errors.New("down")
+	tests := []struct {
+		name        string
+		results     []bool // record 的参数，true 为失败
+		elapsed     bool   // 是否超过 OpenTimeout
+		probeErr    error
+		wantAllow   bool
+		wantState   CircuitState
+		wantChanges []string
+		wantProbes  int
+	}{
+		{
+			name:      "failures below threshold keep it closed",
+			results:   []bool{true, true},
+			wantAllow: true,
+			wantState: CircuitClosed,
+		},
+		{
+			name:      "success resets the failure count",
+			results:   []bool{true, true, false, true, true},
+			wantAllow: true,
+			wantState: CircuitClosed,
+		},
+		{
+			name:        "consecutive failures open it",
+			results:     []bool{true, true, true},
+			wantState:   CircuitOpen,
+			wantChanges: []string{"http://raglite.test: closed -> open"},
+		},
+		{
+			name:      "probe success after OpenTimeout closes it",
+			results:   []bool{true, true, true},
+			elapsed:   true,
+			wantAllow: true,
+			wantState: CircuitClosed,
+			wantChanges: []string{
+				"http://raglite.test: closed -> open",
+				"http://raglite.test: open -> half-open",
+				"http://raglite.test: half-open -> closed",
+			},
+			wantProbes: 1,
+		},
+		{
+			name:      "probe failure reopens it",
+			results:   []bool{true, true, true},
+			elapsed:   true,
+			probeErr:  errDown,
+			wantState: CircuitOpen,
+			wantChanges: []string{
+				"http://raglite.test: closed -> open",
+				"http://raglite.test: open -> half-open",
+				"http://raglite.test: half-open -> open",
+			},
+			wantProbes: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probes := 0
+			b, changes := newTestBreaker(3, time.Hour, func(ctx context.Context) error {
+				probes++
+				return tt.probeErr
+			})
+			for _, failed := range tt.results {
+				b.record(failed)
+			}
+			if tt.elapsed {
+				b.openedAt = b.openedAt.Add(-time.Hour)
+			}
+
+			err := b.allow(context.Background())
+			if tt.wantAllow && err != nil {
+				t.Fatalf("allow() = %v, want nil", err)
+			}
+			if !tt.wantAllow {
+				var openErr *CircuitOpenError
+				if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+					t.Fatalf("allow() = %v, want *CircuitOpenError", err)
+				}
+				if want := b.openedAt.Add(time.Hour); !openErr.RetryAt.Equal(want) {
+					t.Errorf("RetryAt = %v, want %v", openErr.RetryAt, want)
+				}
+			}
+			if b.state != tt.wantState {
+				t.Errorf("state = %s, want %s", b.state, tt.wantState)
+			}
+			if !reflect.DeepEqual(*changes, tt.wantChanges) {
+				t.Errorf("state changes = %q, want %q", *changes, tt.wantChanges)
+			}
+			if probes != tt.wantProbes {
+				t.Errorf("probes = %d, want %d", probes, tt.wantProbes)
+			}
+		})
+	}
+}
+
+func TestCircuitBreakerIgnoresResultsWhileOpen(t *testing.T) {
+	b, _ := newTestBreaker(1, time.Hour, nil)
+	b.record(true)
+	openedAt := b.openedAt
+
+	b.record(false)
+	b.record(true)
+	if b.state != CircuitOpen || !b.openedAt.Equal(openedAt) {
+		t.Errorf("state = %s, openedAt moved = %v, want open and unchanged", b.state, !b.openedAt.Equal(openedAt))
+	}
+}
+
+func TestCircuitBreakerProbeIgnoresCallerCancel(t *testing.T) {
+	b, _ := newTestBreaker(1, time.Hour, func(ctx context.Context) error {
+		return ctx.Err()
+	})
+	b.record(true)
+	b.openedAt = b.openedAt.Add(-time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := b.allow(ctx); err != nil {
+		t.Errorf("allow() = %v, want the probe to run despite the canceled caller", err)
+	}
+}
+
+func TestIsCircuitFailure(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now())
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{"2xx", context.Background(), &http.Response{StatusCode: 200}, nil, false},
+		{"4xx", context.Background(), &http.Response{StatusCode: 429}, nil, false},
+		{"5xx", context.Background(), &http.Response{StatusCode: 503}, nil, true},
+		{"connection error", context.Background(), nil, errors.New("connection refused"), true},
+		{"timeout", context.Background(), nil, context.DeadlineExceeded, true},
+		{"caller deadline exceeded", expired, nil, fmt.Errorf("request: %w", context.DeadlineExceeded), true},
+		{"caller canceled", canceled, nil, context.Canceled, false},
+		{"error after caller canceled", canceled, nil, errors.New("read: connection reset"), false},
+	}
+	for _, tt := range tests {
+		if got := isCircuitFailure(tt.ctx, tt.resp, tt.err); got != tt.want {
+			t.Errorf("%s: isCircuitFailure() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestClientCircuitBreaker(t *testing.T) {
+	srv := newScriptedServer(t, 503, 503)
+	var changes []CircuitState
+	client := newTestClient(t, srv.URL,
+		WithRetryPolicy(fastRetryPolicy(0)),
+		WithCircuitBreaker(CircuitBreakerConfig{
+			FailureThreshold: 2,
+			OpenTimeout:      50 * time.Millisecond,
+			OnStateChange: func(baseURL string, from, to CircuitState) {
+				changes = append(changes, to)
+			},
+		}),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := getDataset(ctx, client); errors.Is(err, ErrCircuitOpen) || err == nil {
+			t.Fatalf("request %d: err = %v, want the 503 error", i+1, err)
+		}
+	}
+	if err := getDataset(ctx, client); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen", err)
+	}
+	if got := srv.count(); got != 2 {
+		t.Errorf("requests = %d, want 2: the open breaker must not send", got)
+	}
+
+	// OpenTimeout 之后通过 /health 探测恢复
+	time.Sleep(60 * time.Millisecond)
+	if err := getDataset(ctx, client); err != nil {
+		t.Fatalf("err = %v, want success after the probe", err)
+	}
+	if got := srv.received()[2].URL.Path; got != "/health" {
+		t.Errorf("probe path = %q, want /health", got)
+	}
+	want := []CircuitState{CircuitOpen, CircuitHalfOpen, CircuitClosed}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("state changes = %v, want %v", changes, want)
+	}
+}
+
+func TestClientCircuitBreakerOpensOnTimeouts(t *testing.T) {
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(hung.Close)
+
+	var opened atomic.Int32
+	client := newTestClient(t, hung.URL,
+		WithRetryPolicy(fastRetryPolicy(0)),
+		WithCircuitBreaker(CircuitBreakerConfig{
+			FailureThreshold: 2,
+			OpenTimeout:      time.Hour,
+			OnStateChange: func(baseURL string, from, to CircuitState) {
+				if to == CircuitOpen {
+					opened.Add(1)
+				}
+			},
+		}),
+	)
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{"context deadline", func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			return getDataset(ctx, client)
+		}},
+		{"call timeout", func() error {
+			_, err := client.Datasets.Get(context.Background(), "ds-1", WithCallTimeout(50*time.Millisecond))
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		if err := tt.call(); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("%s: err = %v, want context.DeadlineExceeded", tt.name, err)
+		}
+	}
+	if err := getDataset(context.Background(), client); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want ErrCircuitOpen after two timeouts", err)
+	}
+	if got := opened.Load(); got != 1 {
+		t.Errorf("OnStateChange to open called %d times, want 1", got)
+	}
+}
+
+func TestClientCircuitBreakerProbeUsesCredentials(t *testing.T) {
+	srv := newScriptedServer(t, http.StatusInternalServerError)
+	srv.apiKey = "k"
+	client := newTestClient(t, srv.URL,
+		WithAPIKey("k"),
+		WithRetryPolicy(fastRetryPolicy(0)),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: 50 * time.Millisecond}),
+	)
+	ctx := context.Background()
+
+	if err := getDataset(ctx, client); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("err = %v, want the 500 error", err)
+	}
+	time.Sleep(60 * time.Millisecond)
+
+	// 探测未认证时服务端返回 401，熔断器会一直保持打开
+	for i := 0; i < 2; i++ {
+		if err := getDataset(ctx, client); err != nil {
+			t.Fatalf("request %d after OpenTimeout: %v", i+1, err)
+		}
+	}
+	probe := srv.received()[1]
+	if probe.URL.Path != "/health" || probe.Header.Get("Authorization") != "Bearer k" {
+		t.Errorf("probe = %s with Authorization %q, want /health with Bearer k",
+			probe.URL.Path, probe.Header.Get("Authorization"))
+	}
+}
@@ -4,18 +4,41 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
 // Client RAGLite SDK 客户端
 type Client struct {
-	baseURL    string
-	apiKey     string
-	httpClient *http.Client
+	endpoints    []*endpoint
+	endpointURLs []string
+	strategy     EndpointStrategy
+	nextEndpoint atomic.Uint64
+
+	credentials CredentialsProvider
+	httpClient  *http.Client
+	retryPolicy *RetryPolicy
+	middlewares []Middleware
+	limits      *limits
+	logger      *slog.Logger
+	logLevel    slog.Level
+
+	skipValidation bool
+
+	circuitConfig *CircuitBreakerConfig
+	breakersMu    sync.Mutex
+	breakers      map[string]*circuitBreaker
+
+	healthInterval time.Duration
+	closed         chan struct{}
+	closeOnce      sync.Once
 
 	// Services
 	Models    *ModelsService
@@ -27,14 +50,13 @@ type Client struct {
 	Health    *HealthService
 }
 
-// NewClient 创建新的 SDK 客户端
+// NewClient 创建新的 SDK 客户端，WithEndpoints 可以添加更多的服务地址
 func NewClient(baseURL string, opts ...Option) (*Client, error) {
 	if baseURL == "" {
 		return nil, fmt.Errorf("baseURL is required")
 	}
 
 	c := &Client{
-		baseURL: baseURL,
 		httpClient: &http.Client{
 			Timeout: 10 * time.Minute,
 			Transport: &http.Transport{
@@ -45,12 +67,19 @@ func NewClient(baseURL string, opts ...Option) (*Client, error) {
 				TLSHandshakeTimeout: 10 * time.Second,
 			},
 		},
+		retryPolicy: DefaultRetryPolicy(),
+		logLevel:    slog.LevelInfo,
 	}
 
 	// 应用选项
 	for _, opt := range opts {
 		opt(c)
 	}
+	c.endpoints = newEndpoints(append([]string{baseURL}, c.endpointURLs...))
+	if c.healthInterval > 0 {
+		c.closed = make(chan struct{})
+		go c.runHealthChecks(c.healthInterval)
+	}
 
 	// 初始化各个服务
 	c.Models = &ModelsService{client: c}
@@ -64,73 +93,264 @@ func NewClient(baseURL string, opts ...Option) (*Client, error) {
 	return c, nil
 }
 
-// do 执行 HTTP 请求
-func (c *Client) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
-	var reqBody io.Reader
-	if body != nil {
-		data, err := json.Marshal(body)
+// do 经过中间件执行 JSON 请求，result 不为 nil 时将响应的 data 解码到 result
+func (c *Client) do(ctx context.Context, op *Operation, body interface{}, result interface{}, opts ...CallOption) error {
+	op.Result = result
+	ctx, cancel := c.prepare(ctx, op, opts)
+	defer cancel()
+	return c.invoke(ctx, op, func(ctx context.Context, op *Operation) error {
+		resp, err := c.sendJSON(ctx, op, body)
 		if err != nil {
-			return fmt.Errorf("failed to marshal request body: %w", err)
+			return err
 		}
-		reqBody = bytes.NewReader(data)
-	}
+		return decodeResponse(resp, op.Result)
+	})
+}
 
-	fullURL := c.baseURL + path
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
+// decodeResponse 读取 2xx 响应并关闭响应体，result 不为 nil 时检查 success 字段并将 data 解码到 result
+func decodeResponse(resp *http.Response, result interface{}) error {
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
-
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+	if result == nil {
+		return nil
 	}
 
-	// 设置 API Key
-	if c.apiKey != "" {
-		req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	var apiResp APIResponse
+	apiResp.Data = result
+	if err := json.Unmarshal(respBody, &apiResp); err != nil {
+		return fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if !apiResp.Success {
+		return newAPIError(resp, respBody)
 	}
+	return nil
+}
 
-	resp, err := c.httpClient.Do(req)
+// requestBody 请求体，open 在每次尝试时被调用，返回本次发送的内容及其长度（未知时为 -1）
+type requestBody struct {
+	contentType string
+	open        func() (io.Reader, int64, error)
+}
+
+// getBody 用作 http.Request.GetBody，Transport 需要重发请求体时调用
+func (b *requestBody) getBody() (io.ReadCloser, error) {
+	r, _, err := b.open()
 	if err != nil {
-		return fmt.Errorf("failed to execute request: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
+	if rc, ok := r.(io.ReadCloser); ok {
+		return rc, nil
+	}
+	return io.NopCloser(r), nil
+}
 
-	respBody, err := io.ReadAll(resp.Body)
+// sendJSON 以 JSON 作为请求体发送请求，返回未读取的 2xx 响应，调用方负责关闭响应体
+func (c *Client) sendJSON(ctx context.Context, op *Operation, body interface{}) (*http.Response, error) {
+	if body == nil {
+		return c.sendRequest(ctx, op, nil)
+	}
+
+	data, err := json.Marshal(body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
+	return c.sendRequest(ctx, op, &requestBody{
+		contentType: "application/json",
+		open: func() (io.Reader, int64, error) {
+			return bytes.NewReader(data), int64(len(data)), nil
+		},
+	})
+}
+
+// sendRequest 构造并发送请求，JSON 请求和文件上传共用：
+// 统一设置追加的请求头、Content-Type 和认证信息，并按重试策略发送。
+// 返回 401 且 credentials 实现了 CredentialsRefresher 时，刷新令牌后重试一次。
+func (c *Client) sendRequest(ctx context.Context, op *Operation, body *requestBody) (*http.Response, error) {
+	// 最近一次使用的令牌
+	var token string
+	newReq := func(baseURL string) (*http.Request, error) {
+		// 每次尝试都重新获取令牌，以便使用轮换后的凭据
+		if c.credentials != nil {
+			var err error
+			token, err = c.credentials.Token(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get credentials: %w", err)
+			}
+		}
 
-	// 检查 HTTP 状态码
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return &APIError{
-				StatusCode: resp.StatusCode,
-				Message:    string(respBody),
+		var reqBody io.Reader
+		contentLength := int64(0)
+		if body != nil {
+			var err error
+			reqBody, contentLength, err = body.open()
+			if err != nil {
+				return nil, err
 			}
 		}
-		apiErr.StatusCode = resp.StatusCode
-		return &apiErr
+
+		req, err := http.NewRequestWithContext(ctx, op.HTTPMethod, baseURL+op.Path, reqBody)
+		if err != nil {
+			if closer, ok := reqBody.(io.Closer); ok {
+				closer.Close()
+			}
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		for k, v := range op.Header {
+			req.Header[k] = v
+		}
+		if body != nil {
+			req.ContentLength = contentLength
+			req.GetBody = body.getBody
+			req.Header.Set("Content-Type", body.contentType)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return req, nil
+	}
+
+	resp, err := c.send(ctx, op, newReq)
+	refresher, ok := c.credentials.(CredentialsRefresher)
+	var apiErr *APIError
+	if !ok || !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		return resp, err
 	}
 
-	// 解析响应
-	if result != nil {
-		var apiResp APIResponse
-		apiResp.Data = result
-		if err := json.Unmarshal(respBody, &apiResp); err != nil {
-			return fmt.Errorf("failed to unmarshal response: %w", err)
+	if refreshErr := refresher.Refresh(ctx, token); refreshErr != nil {
+		return nil, fmt.Errorf("%w (failed to refresh credentials: %v)", err, refreshErr)
+	}
+	resp, retryErr := c.send(ctx, op, newReq)
+	if errors.Is(retryErr, errBodyNotReplayable) {
+		return nil, err
+	}
+	return resp, retryErr
+}
+
+// send 发送请求并返回 2xx 响应，调用方负责关闭响应体
+//
+// newReq 在每次尝试时被调用，以本次选择的服务地址重新构造请求（包括请求体），
+// 临时性失败按照 retryPolicy 以指数退避加抖动的方式重试。
+// 非 2xx 响应会被读取并转换为 *APIError。
+// 配置了限流或并发限制时，每次尝试前都要排队，等待时间累计到 op.QueueTime。
+// 配置了熔断器时，熔断器打开期间直接返回 *CircuitOpenError。
+// 配置了多个服务地址时，失败的请求会立即切换到本次操作还没有尝试过的地址，
+// 不等待也不计入重试次数；非幂等请求只有在连接没有建立时才会切换。
+func (c *Client) send(ctx context.Context, op *Operation, newReq func(baseURL string) (*http.Request, error)) (*http.Response, error) {
+	tried := make(map[*endpoint]bool, len(c.endpoints))
+	retries := 0
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		ep, breaker, err := c.endpointFor(ctx, tried)
+		if err != nil {
+			return nil, err
+		}
+		tried[ep] = true
+
+		var queued time.Duration
+		var release func()
+		if c.limits != nil {
+			queued, release, err = c.limits.acquire(ctx, op.Class())
+			op.QueueTime += queued
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		req, err := newReq(ep.baseURL)
+		if err != nil {
+			if release != nil {
+				release()
+			}
+			// 请求体无法重放时返回上一次的错误
+			if lastErr != nil && errors.Is(err, errBodyNotReplayable) {
+				return nil, lastErr
+			}
+			return nil, err
+		}
+
+		start := time.Now()
+		done := ep.begin()
+		resp, err := c.httpClient.Do(req)
+		if release != nil {
+			releaseLimits := release
+			release = func() {
+				done()
+				releaseLimits()
+			}
+		} else {
+			release = done
+		}
+		if err != nil {
+			release()
+		} else {
+			resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: release}
+		}
+		if c.logger != nil {
+			c.logAttempt(ctx, req, resp, err, attempt, time.Since(start), queued)
+		}
+		op.recordAttempt(req, resp)
+		failed := isCircuitFailure(ctx, resp, err)
+		ep.record(failed)
+		if breaker != nil {
+			breaker.record(failed)
 		}
 
-		if !apiResp.Success {
-			return &APIError{
-				StatusCode: resp.StatusCode,
-				Message:    apiResp.Message,
+		// 带有幂等键的请求可以像幂等方法一样安全地重发
+		idempotent := isIdempotent(req.Method) || req.Header.Get(headerIdempotencyKey) != ""
+		var retry bool
+		if err != nil {
+			err = &TransportError{Method: req.Method, URL: req.URL.String(), Err: err}
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			retry = IsRetryable(err) && idempotent
+		} else {
+			// 检查 HTTP 状态码
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return resp, nil
 			}
+			err = decodeError(resp)
+			retry = c.retryPolicy != nil && c.retryPolicy.retryableStatus(idempotent, resp.StatusCode)
+		}
+
+		if c.hasUntried(tried) && (requestNotSent(err) || (idempotent && IsRetryable(err))) {
+			lastErr = err
+			continue
 		}
+		if !retry || !c.canRetry(retries) {
+			return nil, err
+		}
+
+		var retryAfter time.Duration
+		if resp != nil {
+			retryAfter, _ = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+		if !sleep(ctx, c.retryPolicy.wait(retries, retryAfter)) {
+			return nil, err
+		}
+		lastErr = err
+		retries++
 	}
+}
 
-	return nil
+// canRetry 已经重试 retries 次后是否还能重试
+func (c *Client) canRetry(retries int) bool {
+	return c.retryPolicy != nil && retries < c.retryPolicy.MaxRetries
+}
+
+// decodeError 读取非 2xx 响应并转换为 *APIError
+func decodeError(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	return newAPIError(resp, respBody)
 }
 
 // buildURL 构建带查询参数的 URL
@@ -0,0 +1,317 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// scriptedServer 依次返回 statuses 中的状态码，用完后返回 200，并记录每次请求的方法、请求头和请求体。
+// 设置了 apiKey 时，没有携带 Authorization: Bearer <apiKey> 的请求返回 401，不消耗 statuses
+type scriptedServer struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	statuses   []int
+	header     http.Header
+	apiKey     string
+	requests   []*http.Request
+	bodies     [][]byte
+	successful string
+}
+
+func newScriptedServer(t *testing.T, statuses ...int) *scriptedServer {
+	t.Helper()
+	s := &scriptedServer{statuses: statuses, successful: `{"success":true,"data":{"id":"ds-1"}}`}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, r)
+		s.bodies = append(s.bodies, body)
+		status := http.StatusOK
+		if s.apiKey != "" && r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+			status = http.StatusUnauthorized
+		} else if len(s.statuses) > 0 {
+			status = s.statuses[0]
+			s.statuses = s.statuses[1:]
+		}
+		for k, v := range s.header {
+			w.Header()[k] = v
+		}
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			io.WriteString(w, s.successful)
+			return
+		}
+		io.WriteString(w, `{"success":false,"message":"`+http.StatusText(status)+`"}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *scriptedServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// received 返回已收到请求的副本，处理函数在其他 goroutine 中追加请求，需要加锁读取
+func (s *scriptedServer) received() []*http.Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*http.Request(nil), s.requests...)
+}
+
+// fastRetryPolicy 不等待的重试策略
+func fastRetryPolicy(maxRetries int) *RetryPolicy {
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = maxRetries
+	policy.InitialBackoff = time.Millisecond
+	policy.MaxBackoff = time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+func newTestClient(t *testing.T, baseURL string, opts ...Option) *Client {
+	t.Helper()
+	client, err := NewClient(baseURL, opts...)
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestSendRetries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxRetries   int
+		call         func(ctx context.Context, c *Client) error
+		wantRequests int
+		wantStatus   int
+	}{
+		{
+			name:         "GET retries 5xx until success",
+			statuses:     []int{503, 502},
+			maxRetries:   3,
+			call:         getDataset,
+			wantRequests: 3,
+		},
+		{
+			name:         "GET gives up after MaxRetries",
+			statuses:     []int{503, 503, 503},
+			maxRetries:   2,
+			call:         getDataset,
+			wantRequests: 3,
+			wantStatus:   503,
+		},
+		{
+			name:         "GET does not retry 4xx",
+			statuses:     []int{404},
+			maxRetries:   3,
+			call:         getDataset,
+			wantRequests: 1,
+			wantStatus:   404,
+		},
+		{
+			name:         "POST does not retry 5xx",
+			statuses:     []int{503},
+			maxRetries:   3,
+			call:         createDataset(),
+			wantRequests: 1,
+			wantStatus:   503,
+		},
+		{
+			name:         "POST retries 429",
+			statuses:     []int{429},
+			maxRetries:   3,
+			call:         createDataset(),
+			wantRequests: 2,
+		},
+		{
+			name:         "POST with idempotency key retries 5xx",
+			statuses:     []int{503, 500},
+			maxRetries:   3,
+			call:         createDataset(WithIdempotencyKey("key")),
+			wantRequests: 3,
+		},
+		{
+			name:         "retries disabled",
+			statuses:     []int{503},
+			maxRetries:   0,
+			call:         getDataset,
+			wantRequests: 1,
+			wantStatus:   503,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newScriptedServer(t, tt.statuses...)
+			client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(tt.maxRetries)))
+
+			err := tt.call(context.Background(), client)
+			if got := srv.count(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Fatalf("err = %v, want APIError with status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func getDataset(ctx context.Context, c *Client) error {
+	_, err := c.Datasets.Get(ctx, "ds-1")
+	return err
+}
+
+func createDataset(opts ...CallOption) func(ctx context.Context, c *Client) error {
+	return func(ctx context.Context, c *Client) error {
+		_, err := c.Datasets.Create(ctx, &CreateDatasetRequest{Name: "docs"}, opts...)
+		return err
+	}
+}
+
+func TestSendKeepsIdempotencyKeyAcrossRetries(t *testing.T) {
+	srv := newScriptedServer(t, 503, 503)
+	client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(3)))
+
+	if err := createDataset(WithIdempotencyKey(""))(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, r := range srv.received() {
+		keys = append(keys, r.Header.Get(headerIdempotencyKey))
+	}
+	if len(keys) != 3 || keys[0] == "" || keys[0] != keys[1] || keys[1] != keys[2] {
+		t.Errorf("Idempotency-Key across attempts = %q, want the same generated key", keys)
+	}
+}
+
+func TestSendHonorsRetryAfter(t *testing.T) {
+	srv := newScriptedServer(t, 429)
+	srv.header = http.Header{"Retry-After": {"1"}}
+	client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(1)))
+
+	start := time.Now()
+	if err := getDataset(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the Retry-After of 1s", elapsed)
+	}
+}
+
+func TestSendCapsRetryAfter(t *testing.T) {
+	srv := newScriptedServer(t, 503)
+	srv.header = http.Header{"Retry-After": {"3600"}}
+	policy := fastRetryPolicy(1)
+	policy.MaxRetryAfter = 20 * time.Millisecond
+	client := newTestClient(t, srv.URL, WithRetryPolicy(policy))
+
+	start := time.Now()
+	if err := getDataset(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond || elapsed > time.Second {
+		t.Errorf("retried after %s, want the 20ms MaxRetryAfter instead of the 1h Retry-After", elapsed)
+	}
+}
+
+func TestSendRetryAfterBeyondDeadline(t *testing.T) {
+	srv := newScriptedServer(t, 429)
+	srv.header = http.Header{"Retry-After": {"30"}}
+	client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(3)))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	err := getDataset(ctx, client)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("err = %v, want ErrRateLimited", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("returned after %s, want immediately when Retry-After exceeds the deadline", elapsed)
+	}
+	if got := srv.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+// readOnly 隐藏底层 Reader 的 Seek 和 ReadAt，模拟不可回退的请求体
+type readOnly struct {
+	io.Reader
+}
+
+func TestSendReplaysUploadBody(t *testing.T) {
+	const content = "file content"
+	tests := []struct {
+		name         string
+		file         func() io.Reader
+		wantRequests int
+		wantErr      bool
+	}{
+		{"bytes reader", func() io.Reader { return bytes.NewReader([]byte(content)) }, 2, false},
+		{"seeker only", func() io.Reader { return &seekOnly{bytes.NewReader([]byte(content))} }, 2, false},
+		{"not replayable", func() io.Reader { return readOnly{strings.NewReader(content)} }, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newScriptedServer(t, 429)
+			srv.successful = `{"success":true,"data":{"document_id":"doc-1"}}`
+			client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(3)))
+
+			_, err := client.Documents.Upload(context.Background(), &UploadDocumentRequest{
+				DatasetID: "ds-1",
+				Filename:  "a.txt",
+				File:      tt.file(),
+			})
+			if got := srv.count(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if tt.wantErr {
+				// 无法重放时返回上一次尝试的错误，而不是重放失败的错误
+				if !errors.Is(err, ErrRateLimited) {
+					t.Fatalf("err = %v, want ErrRateLimited from the first attempt", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, body := range srv.bodies {
+				if !bytes.Contains(body, []byte(content)) {
+					t.Errorf("attempt %d body does not contain the file content", i+1)
+				}
+			}
+		})
+	}
+}
+
+// seekOnly 只支持 Seek，不支持 ReadAt
+type seekOnly struct {
+	r *bytes.Reader
+}
+
+func (s *seekOnly) Read(p []byte) (int, error) { return s.r.Read(p) }
+
+func (s *seekOnly) Seek(offset int64, whence int) (int64, error) { return s.r.Seek(offset, whence) }
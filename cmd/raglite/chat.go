@@ -0,0 +1,175 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func init() {
+	register("chat", "interactive multi-turn QA: chat --dataset <id>", chat)
+}
+
+const chatHelp = `Commands:
+  /history       show the conversation history
+  /context       show the context retrieved for the last answer
+  /reset         clear the conversation history
+  /save <file>   save the conversation to a JSON file
+  /exit          quit (Ctrl-D also works)`
+
+func chat(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("chat")
+	datasetID := datasetFlag(fs)
+	topK := fs.Int("top-k", 10, "number of chunks to retrieve")
+	mode := fs.String("mode", "", "retrieval mode: full or smart")
+	threshold := fs.Float64("threshold", 0, "similarity threshold between 0 and 1")
+	maxTurns := fs.Int("max-turns", 10, "number of recent turns to keep in the history, 0 for no limit")
+	maxTokens := fs.Int("max-tokens", 0, "estimated token budget for the history, 0 for no limit")
+	load := fs.String("load", "", "resume a conversation saved with /save or --save")
+	save := fs.String("save", "", "save the conversation to this file on exit")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+
+	var conv *sdk.Conversation
+	if *load != "" {
+		data, err := os.ReadFile(*load)
+		if err != nil {
+			return err
+		}
+		if conv, err = client.RestoreConversation(data); err != nil {
+			return err
+		}
+		fs.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "dataset":
+				conv.SetDatasetID(*datasetID)
+			case "max-turns":
+				conv.SetMaxTurns(*maxTurns)
+			case "max-tokens":
+				conv.SetMaxTokens(*maxTokens)
+			}
+		})
+	} else {
+		conv = client.NewConversation(*datasetID, &sdk.ConversationOptions{
+			MaxTurns:  *maxTurns,
+			MaxTokens: *maxTokens,
+		})
+	}
+	if err := requireDataset(conv.DatasetID()); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stderr, "Chatting with dataset %s, type /help for commands.\n", conv.DatasetID())
+
+	var contexts []sdk.SearchResult
+	scanner := bufio.NewScanner(a.stdin)
+	for {
+		fmt.Fprint(a.stderr, "> ")
+		if !scanner.Scan() {
+			fmt.Fprintln(a.stderr)
+			break
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			name, arg, _ := strings.Cut(line, " ")
+			arg = strings.TrimSpace(arg)
+			switch name {
+			case "/exit", "/quit":
+				return saveConversation(conv, *save)
+			case "/help":
+				fmt.Fprintln(a.stderr, chatHelp)
+			case "/reset":
+				conv.Reset()
+				contexts = nil
+				fmt.Fprintln(a.stderr, "History cleared.")
+			case "/history":
+				for _, m := range conv.History() {
+					fmt.Fprintf(a.stdout, "[%s] %s\n", m.Role, m.Content)
+				}
+			case "/context":
+				if err := printList(a, contexts, contexts, searchColumns); err != nil {
+					return err
+				}
+			case "/save":
+				if arg == "" {
+					arg = *save
+				}
+				if arg == "" {
+					fmt.Fprintln(a.stderr, "usage: /save <file>")
+					continue
+				}
+				if err := saveConversation(conv, arg); err != nil {
+					fmt.Fprintf(a.stderr, "error: %v\n", err)
+					continue
+				}
+				fmt.Fprintf(a.stderr, "Saved to %s.\n", arg)
+			default:
+				fmt.Fprintf(a.stderr, "unknown command %s, type /help for commands\n", name)
+			}
+			continue
+		}
+
+		s, err := conv.AskStream(ctx, &sdk.QARequest{
+			Query:               line,
+			TopK:                *topK,
+			RetrievalMode:       *mode,
+			SimilarityThreshold: *threshold,
+		})
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+			continue
+		}
+		for s.Next() {
+			ev := s.Current()
+			switch ev.Type {
+			case sdk.QAEventAnswer:
+				fmt.Fprint(a.stdout, ev.Delta)
+			case sdk.QAEventDone:
+				fmt.Fprintln(a.stdout)
+				contexts = ev.Context
+			}
+		}
+		s.Close()
+		if err := s.Err(); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			fmt.Fprintf(a.stderr, "error: %v\n", err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return saveConversation(conv, *save)
+}
+
+// saveConversation 将会话保存为 JSON 文件，path 为空时不保存
+func saveConversation(conv *sdk.Conversation, path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(conv, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
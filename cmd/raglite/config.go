@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// 环境变量
+const (
+	envConfig  = "RAGLITE_CONFIG"
+	envBaseURL = "RAGLITE_BASE_URL"
+	envAPIKey  = "RAGLITE_API_KEY"
+	envOutput  = "RAGLITE_OUTPUT"
+)
+
+// defaultBaseURL 未配置时使用的服务地址
+const defaultBaseURL = "http://localhost:8080"
+
+// config 连接配置，也是配置文件的格式
+type config struct {
+	BaseURL string        `json:"base_url,omitempty"`
+	APIKey  string        `json:"api_key,omitempty"`
+	Output  string        `json:"output,omitempty"`
+	Timeout time.Duration `json:"-"`
+
+	// 配置文件中的超时时间，如 "60s"
+	TimeoutString string `json:"timeout,omitempty"`
+}
+
+// globalFlags 全局选项，所有子命令共享
+type globalFlags struct {
+	config  string
+	baseURL string
+	apiKey  string
+	output  string
+	timeout time.Duration
+	debug   bool
+
+	resolved *config
+}
+
+// register 在 FlagSet 中注册全局选项
+func (g *globalFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.config, "config", g.config, "config file path (env "+envConfig+")")
+	fs.StringVar(&g.baseURL, "base-url", g.baseURL, "RAGLite server URL (env "+envBaseURL+")")
+	fs.StringVar(&g.apiKey, "api-key", g.apiKey, "API key (env "+envAPIKey+")")
+	fs.StringVar(&g.output, "output", g.output, "output format: table, json or yaml (env "+envOutput+")")
+	fs.StringVar(&g.output, "o", g.output, "shorthand for --output")
+	fs.DurationVar(&g.timeout, "timeout", g.timeout, "request timeout, e.g. 60s")
+	fs.BoolVar(&g.debug, "debug", g.debug, "log HTTP requests and responses to stderr (secrets are redacted)")
+}
+
+// resolve 合并命令行选项、环境变量和配置文件
+func (g *globalFlags) resolve() (*config, error) {
+	if g.resolved != nil {
+		return g.resolved, nil
+	}
+
+	cfg, err := loadConfig(g.config)
+	if err != nil {
+		return nil, err
+	}
+
+	pick := func(dst *string, flagValue, env string) {
+		if flagValue != "" {
+			*dst = flagValue
+		} else if v := os.Getenv(env); v != "" {
+			*dst = v
+		}
+	}
+	pick(&cfg.BaseURL, g.baseURL, envBaseURL)
+	pick(&cfg.APIKey, g.apiKey, envAPIKey)
+	pick(&cfg.Output, g.output, envOutput)
+	if g.timeout > 0 {
+		cfg.Timeout = g.timeout
+	}
+
+	if cfg.BaseURL == "" {
+		cfg.BaseURL = defaultBaseURL
+	}
+	switch cfg.Output {
+	case "":
+		cfg.Output = "table"
+	case "table", "json", "yaml":
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", cfg.Output)
+	}
+
+	g.resolved = cfg
+	return cfg, nil
+}
+
+// loadConfig 读取配置文件，未指定路径且默认文件不存在时返回空配置
+func loadConfig(path string) (*config, error) {
+	explicit := true
+	if path == "" {
+		path = os.Getenv(envConfig)
+	}
+	if path == "" {
+		explicit = false
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return &config{}, nil
+		}
+		path = filepath.Join(dir, "raglite", "config.json")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, fs.ErrNotExist) {
+			return &config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	if cfg.TimeoutString != "" {
+		cfg.Timeout, err = time.ParseDuration(cfg.TimeoutString)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout in config %s: %w", path, err)
+		}
+	}
+	return &cfg, nil
+}
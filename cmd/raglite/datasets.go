@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func init() {
+	register("datasets list", "list datasets", datasetsList)
+	register("datasets get", "show a dataset: datasets get <dataset-id>", datasetsGet)
+	register("datasets create", "create a dataset", datasetsCreate)
+	register("datasets stats", "show dataset statistics: datasets stats <dataset-id>", datasetsStats)
+	register("datasets delete", "delete datasets: datasets delete <dataset-id>...", datasetsDelete)
+}
+
+var datasetColumns = []column[sdk.Dataset]{
+	{"ID", func(d sdk.Dataset) string { return d.ID }},
+	{"NAME", func(d sdk.Dataset) string { return d.Name }},
+	{"STATUS", func(d sdk.Dataset) string { return d.Status }},
+	{"DENSE MODEL", func(d sdk.Dataset) string { return d.DenseModelID }},
+	{"CHUNK SIZE", func(d sdk.Dataset) string { return fmt.Sprint(d.Config.ChunkSize) }},
+	{"CREATED", func(d sdk.Dataset) string { return formatTime(d.CreatedAt) }},
+}
+
+func datasetsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("datasets list")
+	req := &sdk.ListDatasetsRequest{}
+	fs.StringVar(&req.Status, "status", "", "filter by status")
+	fs.IntVar(&req.Page, "page", 0, "page number")
+	fs.IntVar(&req.PageSize, "page-size", 0, "page size")
+	all := fs.Bool("all", false, "list all pages")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		datasets, err := client.Datasets.ListPager(req, nil).All(ctx)
+		if err != nil {
+			return err
+		}
+		return printList(a, datasets, datasets, datasetColumns)
+	}
+
+	resp, err := client.Datasets.List(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printList(a, resp, resp.Datasets, datasetColumns)
+}
+
+func datasetsGet(ctx context.Context, a *app, args []string) error {
+	args, err := parse(a.flagSet("datasets get"), args)
+	if err != nil {
+		return err
+	}
+	if err := requireArgs(args, 1, "<dataset-id>"); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	dataset, err := client.Datasets.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printObject(dataset)
+}
+
+func datasetsCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("datasets create")
+	file := fs.String("file", "", "read the request from a JSON file ('-' for stdin); other flags override it")
+	name := fs.String("name", "", "dataset name")
+	description := fs.String("description", "", "dataset description")
+	denseModel := fs.String("dense-model", "", "dense embedding model ID")
+	sparseModel := fs.String("sparse-model", "", "sparse embedding model ID")
+	analysisModel := fs.String("analysis-model", "", "analysis model ID")
+	rerankerModel := fs.String("reranker-model", "", "reranker model ID")
+	visionModel := fs.String("vision-model", "", "vision model ID")
+	chunkSize := fs.Int("chunk-size", 0, "chunk size in tokens")
+	chunkOverlap := fs.Int("chunk-overlap", 0, "chunk overlap in tokens")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	req := &sdk.CreateDatasetRequest{}
+	if err := a.readRequest(*file, req); err != nil {
+		return err
+	}
+	if *name != "" {
+		req.Name = *name
+	}
+	if *description != "" {
+		req.Description = *description
+	}
+	setOptional := func(dst **string, v string) {
+		if v != "" {
+			*dst = sdk.Ptr(v)
+		}
+	}
+	setOptional(&req.DenseModelID, *denseModel)
+	setOptional(&req.SparseModelID, *sparseModel)
+	setOptional(&req.AnalysisModelID, *analysisModel)
+	setOptional(&req.RerankerModelID, *rerankerModel)
+	setOptional(&req.VisionModelID, *visionModel)
+	if *chunkSize > 0 {
+		req.Config.ChunkSize = *chunkSize
+	}
+	if *chunkOverlap > 0 {
+		req.Config.ChunkOverlap = *chunkOverlap
+	}
+	if req.Name == "" {
+		return fmt.Errorf("--name is required")
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	dataset, err := client.Datasets.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printObject(dataset)
+}
+
+func datasetsStats(ctx context.Context, a *app, args []string) error {
+	args, err := parse(a.flagSet("datasets stats"), args)
+	if err != nil {
+		return err
+	}
+	if err := requireArgs(args, 1, "<dataset-id>"); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	stats, err := client.Datasets.GetStats(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := a.global.resolve()
+	if err != nil {
+		return err
+	}
+	if cfg.Output != "table" {
+		return a.encode(stats)
+	}
+	return a.printObject(struct {
+		Dataset        string `json:"dataset"`
+		TotalDocuments int64  `json:"total_documents"`
+		PendingDocs    int64  `json:"pending_docs"`
+		ProcessingDocs int64  `json:"processing_docs"`
+		CompletedDocs  int64  `json:"completed_docs"`
+		FailedDocs     int64  `json:"failed_docs"`
+		TotalFileSize  int64  `json:"total_file_size"`
+	}{
+		Dataset:        stats.Dataset.Name,
+		TotalDocuments: stats.TotalDocuments,
+		PendingDocs:    stats.PendingDocs,
+		ProcessingDocs: stats.ProcessingDocs,
+		CompletedDocs:  stats.CompletedDocs,
+		FailedDocs:     stats.FailedDocs,
+		TotalFileSize:  stats.TotalFileSize,
+	})
+}
+
+func datasetsDelete(ctx context.Context, a *app, args []string) error {
+	args, err := parse(a.flagSet("datasets delete"), args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected <dataset-id>...")
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	for _, id := range args {
+		if err := client.Datasets.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete dataset %s: %w", id, err)
+		}
+	}
+	return a.printMessage(map[string]interface{}{"deleted": args}, "Deleted %d dataset(s)", len(args))
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func init() {
+	register("documents upload", "upload documents: documents upload --dataset <id> <file>...", documentsUpload)
+	register("documents list", "list documents: documents list --dataset <id>", documentsList)
+	register("documents get", "show a document: documents get --dataset <id> <document-id>", documentsGet)
+	register("documents update", "update document tags/metadata: documents update --dataset <id> <document-id>", documentsUpdate)
+	register("documents reindex", "reindex documents: documents reindex --dataset <id> <document-id>...", documentsReindex)
+	register("documents delete", "delete documents: documents delete --dataset <id> <document-id>...", documentsDelete)
+}
+
+var documentColumns = []column[sdk.Document]{
+	{"ID", func(d sdk.Document) string { return d.ID }},
+	{"TITLE", func(d sdk.Document) string { return d.Title }},
+	{"FILENAME", func(d sdk.Document) string { return d.Filename }},
+	{"STATUS", func(d sdk.Document) string { return d.Status }},
+	{"SIZE", func(d sdk.Document) string { return fmt.Sprint(d.FileSize) }},
+	{"UPDATED", func(d sdk.Document) string { return formatTime(d.UpdatedAt) }},
+}
+
+// datasetFlag 注册必填的 --dataset 选项
+func datasetFlag(fs *flag.FlagSet) *string {
+	return fs.String("dataset", "", "dataset ID (required)")
+}
+
+// requireDataset 检查 --dataset 是否已设置
+func requireDataset(datasetID string) error {
+	if datasetID == "" {
+		return fmt.Errorf("--dataset is required")
+	}
+	return nil
+}
+
+// parseMetadata 解析 JSON 格式的 metadata 选项
+func parseMetadata(s string) (map[string]interface{}, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &metadata); err != nil {
+		return nil, fmt.Errorf("invalid --metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+func documentsUpload(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("documents upload")
+	datasetID := datasetFlag(fs)
+	documentID := fs.String("document-id", "", "replace an existing document (single file only)")
+	title := fs.String("title", "", "document title (single file only)")
+	tags := fs.String("tags", "", "comma separated tags")
+	metadataJSON := fs.String("metadata", "", "metadata as a JSON object")
+	extractKeywords := fs.Bool("extract-keywords", false, "extract keywords after parsing")
+	keywordsOnly := fs.Bool("keywords-only", false, "only extract keywords, do not index the content")
+	concurrency := fs.Int("concurrency", 4, "number of concurrent uploads")
+	wait := fs.Bool("wait", false, "wait until the documents are processed")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected <file>...")
+	}
+	if len(args) > 1 && (*documentID != "" || *title != "") {
+		return fmt.Errorf("--document-id and --title can only be used with a single file")
+	}
+	metadata, err := parseMetadata(*metadataJSON)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+
+	reqs := make(chan *sdk.UploadDocumentRequest, len(args))
+	for _, path := range args {
+		file, err := os.Open(path)
+		if err != nil {
+			close(reqs)
+			for req := range reqs {
+				req.File.(*os.File).Close()
+			}
+			return err
+		}
+		reqs <- &sdk.UploadDocumentRequest{
+			DatasetID:        *datasetID,
+			DocumentID:       *documentID,
+			File:             file,
+			Title:            *title,
+			Filename:         filepath.Base(path),
+			Tags:             splitList(*tags),
+			Metadata:         metadata,
+			ExtractKeywords:  *extractKeywords,
+			KeywordsOnlyMode: *keywordsOnly,
+		}
+	}
+	close(reqs)
+
+	report, err := client.Documents.UploadMany(ctx, reqs, &sdk.BulkUploadOptions{
+		Concurrency:       *concurrency,
+		WaitForProcessing: *wait,
+		OnResult: func(result *sdk.BulkUploadResult) {
+			result.Request.File.(*os.File).Close()
+			if result.Err != nil {
+				fmt.Fprintf(a.stderr, "%s: %v\n", args[result.Index], result.Err)
+			}
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	type uploadResult struct {
+		File       string `json:"file"`
+		DocumentID string `json:"document_id,omitempty"`
+		Status     string `json:"status,omitempty"`
+		Error      string `json:"error,omitempty"`
+	}
+	results := make([]uploadResult, len(report.Results))
+	for i, r := range report.Results {
+		results[i].File = args[r.Index]
+		if r.Response != nil {
+			results[i].DocumentID = r.Response.DocumentID
+			results[i].Status = r.Response.Status
+		}
+		if r.Document != nil {
+			results[i].Status = r.Document.Status
+		}
+		if r.Err != nil {
+			results[i].Error = r.Err.Error()
+		}
+	}
+	if err := printList(a, results, results, []column[uploadResult]{
+		{"FILE", func(r uploadResult) string { return r.File }},
+		{"DOCUMENT ID", func(r uploadResult) string { return r.DocumentID }},
+		{"STATUS", func(r uploadResult) string { return r.Status }},
+		{"ERROR", func(r uploadResult) string { return r.Error }},
+	}); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d uploads failed", report.Failed, len(report.Results))
+	}
+	return nil
+}
+
+func documentsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("documents list")
+	datasetID := datasetFlag(fs)
+	ids := fs.String("ids", "", "comma separated document IDs to filter")
+	page := fs.Int("page", 0, "page number")
+	pageSize := fs.Int("page-size", 0, "page size")
+	all := fs.Bool("all", false, "list all pages")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+
+	req := &sdk.ListDocumentsRequest{
+		DatasetID:   *datasetID,
+		DocumentIDs: splitList(*ids),
+		Page:        *page,
+		PageSize:    *pageSize,
+	}
+	if *all {
+		docs, err := client.Documents.ListPager(req, nil).All(ctx)
+		if err != nil {
+			return err
+		}
+		return printList(a, docs, docs, documentColumns)
+	}
+
+	resp, err := client.Documents.List(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printList(a, resp, resp.Documents, documentColumns)
+}
+
+func documentsGet(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("documents get")
+	datasetID := datasetFlag(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+	if err := requireArgs(args, 1, "<document-id>"); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	doc, err := client.Documents.Get(ctx, *datasetID, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printObject(doc)
+}
+
+func documentsUpdate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("documents update")
+	datasetID := datasetFlag(fs)
+	tags := fs.String("tags", "", "comma separated tags, replaces existing tags")
+	metadataJSON := fs.String("metadata", "", "metadata as a JSON object, replaces existing metadata")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+	if err := requireArgs(args, 1, "<document-id>"); err != nil {
+		return err
+	}
+	metadata, err := parseMetadata(*metadataJSON)
+	if err != nil {
+		return err
+	}
+
+	req := &sdk.UpdateDocumentRequest{
+		DatasetID:  *datasetID,
+		DocumentID: args[0],
+		Metadata:   metadata,
+	}
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "tags" {
+			req.Tags = append([]string{}, splitList(*tags)...)
+		}
+	})
+	if req.Metadata == nil && req.Tags == nil {
+		return fmt.Errorf("nothing to update, use --tags or --metadata")
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	doc, err := client.Documents.Update(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printObject(doc)
+}
+
+func documentsReindex(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("documents reindex")
+	datasetID := datasetFlag(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected <document-id>...")
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	var results []*sdk.ReindexResponse
+	for _, id := range args {
+		resp, err := client.Documents.Reindex(ctx, *datasetID, id)
+		if err != nil {
+			return fmt.Errorf("failed to reindex document %s: %w", id, err)
+		}
+		results = append(results, resp)
+	}
+	return printList(a, results, results, []column[*sdk.ReindexResponse]{
+		{"DOCUMENT ID", func(r *sdk.ReindexResponse) string { return r.DocumentID }},
+		{"MESSAGE", func(r *sdk.ReindexResponse) string { return r.Message }},
+	})
+}
+
+func documentsDelete(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("documents delete")
+	datasetID := datasetFlag(fs)
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected <document-id>...")
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		err = client.Documents.Delete(ctx, *datasetID, args[0])
+	} else {
+		err = client.Documents.BatchDelete(ctx, &sdk.BatchDeleteDocumentsRequest{
+			DatasetID:   *datasetID,
+			DocumentIDs: args,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return a.printMessage(map[string]interface{}{"deleted": args}, "Deleted %d document(s)", len(args))
+}
@@ -0,0 +1,185 @@
+// Command raglite 是 RAGLite 的命令行工具，基于 SDK 的公开接口实现。
+//
+// 用法：
+//
+//	raglite [全局选项] <命令> [子命令] [选项] [参数]
+//
+// 连接配置的优先级为：命令行选项 > 环境变量（RAGLITE_BASE_URL、RAGLITE_API_KEY、RAGLITE_OUTPUT）>
+// 配置文件（默认 $XDG_CONFIG_HOME/raglite/config.json，可通过 --config 或 RAGLITE_CONFIG 指定）。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// command 子命令
+type command struct {
+	usage string
+	run   func(ctx context.Context, a *app, args []string) error
+}
+
+// commands 按 "资源 动作" 组织的命令表，没有动作的命令使用资源名作为键
+var commands = map[string]command{}
+
+// register 注册命令
+func register(name, usage string, run func(ctx context.Context, a *app, args []string) error) {
+	commands[name] = command{usage: usage, run: run}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	a := &app{stdout: os.Stdout, stderr: os.Stderr, stdin: os.Stdin}
+	if err := a.run(ctx, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "raglite: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// app 命令行运行时状态
+type app struct {
+	stdout io.Writer
+	stderr io.Writer
+	stdin  io.Reader
+
+	global globalFlags
+	client *sdk.Client
+}
+
+// run 解析全局选项并执行命令
+func (a *app) run(ctx context.Context, args []string) error {
+	fs := a.flagSet("raglite")
+	fs.Usage = func() { a.usage() }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	args = fs.Args()
+	if len(args) == 0 {
+		a.usage()
+		return flag.ErrHelp
+	}
+
+	name, rest := args[0], args[1:]
+	if len(rest) > 0 {
+		if _, ok := commands[name+" "+rest[0]]; ok {
+			name, rest = name+" "+rest[0], rest[1:]
+		}
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		a.usage()
+		return fmt.Errorf("unknown command %q", strings.Join(args[:min(len(args), 2)], " "))
+	}
+	return cmd.run(ctx, a, rest)
+}
+
+// usage 输出帮助信息
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "Usage: raglite [global flags] <command> [flags] [args]")
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Commands:")
+
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %-20s %s\n", name, commands[name].usage)
+	}
+
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "Global flags (also accepted after the command):")
+	fs := flag.NewFlagSet("raglite", flag.ContinueOnError)
+	(&globalFlags{}).register(fs)
+	fs.SetOutput(a.stderr)
+	fs.PrintDefaults()
+}
+
+// flagSet 创建包含全局选项的 FlagSet
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	a.global.register(fs)
+	return fs
+}
+
+// parse 解析选项，允许选项和位置参数交替出现
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		if args[0] == "--" {
+			return append(positional, args[1:]...), nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// sdkClient 按配置创建 SDK 客户端
+func (a *app) sdkClient() (*sdk.Client, error) {
+	if a.client != nil {
+		return a.client, nil
+	}
+
+	cfg, err := a.global.resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []sdk.Option
+	if cfg.APIKey != "" {
+		opts = append(opts, sdk.WithAPIKey(cfg.APIKey))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, sdk.WithTimeout(cfg.Timeout))
+	}
+	if a.global.debug {
+		handler := slog.NewTextHandler(a.stderr, &slog.HandlerOptions{Level: slog.LevelDebug})
+		opts = append(opts, sdk.WithLogger(slog.New(handler)))
+	}
+
+	a.client, err = sdk.NewClient(cfg.BaseURL, opts...)
+	return a.client, err
+}
+
+// requireArgs 检查位置参数数量
+func requireArgs(args []string, n int, names string) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %s", names)
+	}
+	return nil
+}
+
+// splitList 拆分逗号分隔的列表，忽略空项
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
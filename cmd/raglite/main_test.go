@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+	"github.com/chaitin/raglite-go-sdk/sdktest"
+)
+
+// isolateConfig 清除环境变量并使用空的默认配置目录
+func isolateConfig(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	for _, env := range []string{envConfig, envBaseURL, envAPIKey, envOutput} {
+		t.Setenv(env, "")
+	}
+}
+
+// runCLI 执行命令并返回标准输出和标准错误
+func runCLI(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	var out, errOut bytes.Buffer
+	a := &app{stdout: &out, stderr: &errOut, stdin: strings.NewReader("")}
+	err = a.run(context.Background(), args)
+	return out.String(), errOut.String(), err
+}
+
+func TestDatasetCommands(t *testing.T) {
+	isolateConfig(t)
+	srv := sdktest.NewServer(sdktest.WithAPIKey("sk-test"))
+	defer srv.Close()
+	conn := []string{"--base-url", srv.URL, "--api-key", "sk-test"}
+	cli := func(args ...string) string {
+		t.Helper()
+		out, _, err := runCLI(t, append(conn, args...)...)
+		if err != nil {
+			t.Fatalf("raglite %s: %v", strings.Join(args, " "), err)
+		}
+		return out
+	}
+
+	// 全局选项也可以写在命令之后
+	var created sdk.Dataset
+	out := cli("datasets", "create", "--name", "FAQ: billing", "--chunk-size", "256", "-o", "json")
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatalf("json output %q: %v", out, err)
+	}
+	if created.ID == "" || created.Name != "FAQ: billing" || created.Config.ChunkSize != 256 {
+		t.Fatalf("created = %+v", created)
+	}
+
+	out = cli("-o", "yaml", "datasets", "get", created.ID)
+	for _, want := range []string{"id: " + created.ID + "\n", `name: "FAQ: billing"` + "\n", "config:\n  chunk_size: 256\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("yaml output missing %q:\n%s", want, out)
+		}
+	}
+
+	out = cli("datasets", "get", created.ID)
+	if !strings.Contains(out, "NAME") || !strings.Contains(out, "FAQ: billing") {
+		t.Errorf("table output:\n%s", out)
+	}
+
+	lines := strings.Split(strings.TrimSpace(cli("datasets", "list")), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID") || !strings.HasPrefix(lines[1], created.ID) {
+		t.Errorf("list output:\n%s", strings.Join(lines, "\n"))
+	}
+
+	if out := cli("datasets", "delete", created.ID); out != "Deleted 1 dataset(s)\n" {
+		t.Errorf("delete output = %q", out)
+	}
+	if _, _, err := runCLI(t, append(conn, "datasets", "get", created.ID)...); !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("get after delete = %v, want ErrNotFound", err)
+	}
+	if _, _, err := runCLI(t, "--base-url", srv.URL, "health"); !errors.Is(err, sdk.ErrUnauthorized) {
+		t.Errorf("request without API key = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestQACommand(t *testing.T) {
+	isolateConfig(t)
+	srv := sdktest.NewServer()
+	defer srv.Close()
+	dataset := srv.AddDataset(sdk.Dataset{Name: "docs"})
+	srv.ScriptQA("how to reset", "Open settings.\nClick reset.")
+
+	for _, stream := range []bool{false, true} {
+		args := []string{"--base-url", srv.URL, "qa", "--dataset", dataset.ID, "how", "to", "reset"}
+		if stream {
+			args = append(args, "--stream")
+		}
+		out, _, err := runCLI(t, args...)
+		if err != nil {
+			t.Fatalf("stream=%v: %v", stream, err)
+		}
+		if out != "Open settings.\nClick reset.\n" {
+			t.Errorf("stream=%v: output = %q", stream, out)
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	isolateConfig(t)
+	srv := sdktest.NewServer()
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"unknown command", []string{"datasets", "frob"}, `unknown command "datasets frob"`},
+		{"missing argument", []string{"datasets", "get"}, "expected <dataset-id>"},
+		{"too many arguments", []string{"datasets", "get", "a", "b"}, "expected <dataset-id>"},
+		{"missing required flag", []string{"datasets", "create"}, "--name is required"},
+		{"missing dataset", []string{"search", "query"}, "--dataset"},
+		{"unsupported output", []string{"-o", "xml", "health"}, `unsupported output format "xml"`},
+		{"missing config file", []string{"--config", filepath.Join(t.TempDir(), "missing.json"), "health"}, "failed to read config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := runCLI(t, append([]string{"--base-url", srv.URL}, tt.args...)...)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// 没有命令时输出帮助
+	_, stderr, err := runCLI(t)
+	if !errors.Is(err, flag.ErrHelp) || !strings.Contains(stderr, "datasets list") {
+		t.Errorf("no command: err = %v, stderr:\n%s", err, stderr)
+	}
+	if got := len(srv.Requests()); got != 0 {
+		t.Errorf("requests = %d, want no request for invalid invocations", got)
+	}
+}
+
+func TestResolveConfig(t *testing.T) {
+	isolateConfig(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	data := `{"base_url":"http://file","api_key":"file-key","output":"json","timeout":"45s"}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		env   map[string]string
+		flags globalFlags
+		want  config
+	}{
+		{
+			name: "defaults",
+			want: config{BaseURL: defaultBaseURL, Output: "table"},
+		},
+		{
+			name:  "config file",
+			flags: globalFlags{config: path},
+			want:  config{BaseURL: "http://file", APIKey: "file-key", Output: "json", Timeout: 45 * time.Second},
+		},
+		{
+			name: "config file from env",
+			env:  map[string]string{envConfig: path},
+			want: config{BaseURL: "http://file", APIKey: "file-key", Output: "json", Timeout: 45 * time.Second},
+		},
+		{
+			name:  "env overrides the config file",
+			env:   map[string]string{envBaseURL: "http://env", envOutput: "yaml"},
+			flags: globalFlags{config: path},
+			want:  config{BaseURL: "http://env", APIKey: "file-key", Output: "yaml", Timeout: 45 * time.Second},
+		},
+		{
+			name:  "flags override env",
+			env:   map[string]string{envBaseURL: "http://env", envAPIKey: "env-key"},
+			flags: globalFlags{config: path, baseURL: "http://flag", output: "table", timeout: time.Second},
+			want:  config{BaseURL: "http://flag", APIKey: "env-key", Output: "table", Timeout: time.Second},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			cfg, err := tt.flags.resolve()
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.TimeoutString = ""
+			if *cfg != tt.want {
+				t.Errorf("resolve() = %+v, want %+v", *cfg, tt.want)
+			}
+		})
+	}
+}
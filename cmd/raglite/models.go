@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func init() {
+	register("models list", "list AI models", modelsList)
+	register("models get", "show an AI model: models get <model-id>", modelsGet)
+	register("models create", "create an AI model", modelsCreate)
+	register("models upsert", "create or update an AI model by API base and model name", modelsUpsert)
+	register("models check", "check an AI model configuration", modelsCheck)
+	register("models delete", "delete AI models: models delete <model-id>...", modelsDelete)
+}
+
+var modelColumns = []column[sdk.AIModel]{
+	{"ID", func(m sdk.AIModel) string { return m.ID }},
+	{"NAME", func(m sdk.AIModel) string { return m.Name }},
+	{"TYPE", func(m sdk.AIModel) string { return m.ModelType }},
+	{"PROVIDER", func(m sdk.AIModel) string { return m.Provider }},
+	{"MODEL", func(m sdk.AIModel) string { return m.ModelName }},
+	{"STATUS", func(m sdk.AIModel) string { return m.Status }},
+	{"DEFAULT", func(m sdk.AIModel) string { return formatBool(m.IsDefault) }},
+}
+
+func modelsList(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("models list")
+	req := &sdk.ListModelsRequest{}
+	fs.StringVar(&req.ModelType, "type", "", "filter by model type")
+	fs.StringVar(&req.Provider, "provider", "", "filter by provider")
+	fs.StringVar(&req.Status, "status", "", "filter by status")
+	fs.IntVar(&req.Page, "page", 0, "page number")
+	fs.IntVar(&req.PageSize, "page-size", 0, "page size")
+	all := fs.Bool("all", false, "list all pages")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+
+	if *all {
+		models, err := client.Models.ListPager(req, nil).All(ctx)
+		if err != nil {
+			return err
+		}
+		return printList(a, models, models, modelColumns)
+	}
+
+	resp, err := client.Models.List(ctx, req)
+	if err != nil {
+		return err
+	}
+	return printList(a, resp, resp.Models, modelColumns)
+}
+
+func modelsGet(ctx context.Context, a *app, args []string) error {
+	args, err := parse(a.flagSet("models get"), args)
+	if err != nil {
+		return err
+	}
+	if err := requireArgs(args, 1, "<model-id>"); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	model, err := client.Models.Get(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return a.printObject(model)
+}
+
+// modelFlags 创建和 upsert 共用的选项
+type modelFlags struct {
+	file        string
+	name        string
+	description string
+	modelType   string
+	provider    string
+	modelName   string
+	apiKey      string
+	apiBase     string
+	isDefault   bool
+}
+
+func (f *modelFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.file, "file", "", "read the request from a JSON file ('-' for stdin); other flags override it")
+	fs.StringVar(&f.name, "name", "", "model display name")
+	fs.StringVar(&f.description, "description", "", "model description")
+	fs.StringVar(&f.modelType, "type", "", "model type, e.g. chat, embedding, rerank")
+	fs.StringVar(&f.provider, "provider", "", "model provider")
+	fs.StringVar(&f.modelName, "model", "", "provider model name")
+	fs.StringVar(&f.apiKey, "model-api-key", "", "API key of the model provider")
+	fs.StringVar(&f.apiBase, "api-base", "", "API base URL of the model provider")
+	fs.BoolVar(&f.isDefault, "default", false, "mark as the default model")
+}
+
+// apply 将命令行选项写入请求中对应的字段
+func (f *modelFlags) apply(name, description, modelType, provider, modelName *string, config *sdk.AIModelConfig, isDefault *bool) {
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(name, f.name)
+	set(description, f.description)
+	set(modelType, f.modelType)
+	set(provider, f.provider)
+	set(modelName, f.modelName)
+	set(&config.APIKey, f.apiKey)
+	set(&config.APIBase, f.apiBase)
+	if f.isDefault {
+		*isDefault = true
+	}
+}
+
+func modelsCreate(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("models create")
+	var f modelFlags
+	f.register(fs)
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	req := &sdk.CreateModelRequest{}
+	if err := a.readRequest(f.file, req); err != nil {
+		return err
+	}
+	f.apply(&req.Name, &req.Description, &req.ModelType, &req.Provider, &req.ModelName, &req.Config, &req.IsDefault)
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	model, err := client.Models.Create(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printObject(model)
+}
+
+func modelsUpsert(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("models upsert")
+	var f modelFlags
+	f.register(fs)
+	active := fs.Bool("active", false, "mark the model as active")
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	req := &sdk.UpsertModelRequest{}
+	if err := a.readRequest(f.file, req); err != nil {
+		return err
+	}
+	f.apply(&req.Name, &req.Description, &req.ModelType, &req.Provider, &req.ModelName, &req.Config, &req.IsDefault)
+	if *active {
+		req.IsActive = true
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Models.Upsert(ctx, req)
+	if err != nil {
+		return err
+	}
+	return a.printMessage(resp, "Model %s: %s (ID: %s)", resp.Action, resp.Model.Name, resp.Model.ID)
+}
+
+func modelsCheck(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("models check")
+	var f modelFlags
+	f.register(fs)
+	if _, err := parse(fs, args); err != nil {
+		return err
+	}
+
+	req := &sdk.CheckModelRequest{}
+	if err := a.readRequest(f.file, req); err != nil {
+		return err
+	}
+	var name, description, modelType string
+	var isDefault bool
+	f.apply(&name, &description, &modelType, &req.Provider, &req.ModelName, &req.Config, &isDefault)
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Models.Check(ctx, req)
+	if err != nil {
+		return err
+	}
+	if !resp.Valid {
+		return a.printMessage(resp, "Model configuration is invalid: %s", resp.Error)
+	}
+	return a.printMessage(resp, "Model configuration is valid")
+}
+
+func modelsDelete(ctx context.Context, a *app, args []string) error {
+	args, err := parse(a.flagSet("models delete"), args)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected <model-id>...")
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	for _, id := range args {
+		if err := client.Models.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete model %s: %w", id, err)
+		}
+	}
+	return a.printMessage(map[string]interface{}{"deleted": args}, "Deleted %d model(s)", len(args))
+}
+
+// readRequest 从 JSON 文件读取请求，path 为空时不做任何事，为 "-" 时读取标准输入
+func (a *app) readRequest(path string, req interface{}) error {
+	if path == "" {
+		return nil
+	}
+
+	var (
+		data []byte
+		err  error
+	)
+	if path == "-" {
+		data, err = io.ReadAll(a.stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	if err := json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("failed to parse request: %w", err)
+	}
+	return nil
+}
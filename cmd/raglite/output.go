@@ -0,0 +1,357 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// column 表格中的一列
+type column[T any] struct {
+	header string
+	value  func(item T) string
+}
+
+// printList 输出列表：table 格式按列输出 items，json/yaml 格式输出完整的 v
+func printList[T any](a *app, v interface{}, items []T, columns []column[T]) error {
+	cfg, err := a.global.resolve()
+	if err != nil {
+		return err
+	}
+	if cfg.Output != "table" {
+		return a.encode(v)
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, item := range items {
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			values[i] = cell(col.value(item))
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
+
+// printObject 输出单个对象：table 格式按字段逐行输出，json/yaml 格式输出完整的 v
+func (a *app) printObject(v interface{}) error {
+	cfg, err := a.global.resolve()
+	if err != nil {
+		return err
+	}
+	if cfg.Output != "table" {
+		return a.encode(v)
+	}
+
+	fields, err := orderedJSON(v)
+	if err != nil {
+		return err
+	}
+	obj, ok := fields.(*orderedMap)
+	if !ok {
+		fmt.Fprintln(a.stdout, scalarText(fields))
+		return nil
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 4, 2, ' ', 0)
+	for _, key := range obj.keys {
+		fmt.Fprintf(w, "%s\t%s\n", strings.ToUpper(key), cell(scalarText(obj.values[key])))
+	}
+	return w.Flush()
+}
+
+// printMessage 输出操作结果，json/yaml 格式输出 v
+func (a *app) printMessage(v interface{}, format string, args ...interface{}) error {
+	cfg, err := a.global.resolve()
+	if err != nil {
+		return err
+	}
+	if cfg.Output != "table" {
+		return a.encode(v)
+	}
+	fmt.Fprintf(a.stdout, format+"\n", args...)
+	return nil
+}
+
+// encode 以 json 或 yaml 格式输出
+func (a *app) encode(v interface{}) error {
+	cfg, err := a.global.resolve()
+	if err != nil {
+		return err
+	}
+	if cfg.Output == "yaml" {
+		node, err := orderedJSON(v)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		writeYAML(&buf, node, 0)
+		_, err = a.stdout.Write(buf.Bytes())
+		return err
+	}
+
+	enc := json.NewEncoder(a.stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(v)
+}
+
+// cell 表格单元格内容，去掉换行并截断过长内容
+func cell(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > 80 {
+		return string(r[:77]) + "..."
+	}
+	return s
+}
+
+// formatTime 格式化时间，零值输出为空
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// formatBool 格式化布尔值
+func formatBool(b bool) string {
+	if b {
+		return "yes"
+	}
+	return ""
+}
+
+// orderedMap 保持字段顺序的 JSON 对象
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// orderedJSON 将 v 编码为 JSON 后重新解码，对象保持字段顺序，数字保持原样
+func orderedJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeOrdered(dec)
+}
+
+func decodeOrdered(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch tok {
+	case json.Delim('{'):
+		m := &orderedMap{values: make(map[string]interface{})}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := keyTok.(string)
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := m.values[key]; !ok {
+				m.keys = append(m.keys, key)
+			}
+			m.values[key] = value
+		}
+		_, err := dec.Token()
+		return m, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// scalarText 表格中的值：标量直接输出，对象和数组输出为紧凑 JSON
+func scalarText(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	var buf bytes.Buffer
+	writeFlowJSON(&buf, v)
+	return buf.String()
+}
+
+// writeFlowJSON 将有序结构写为紧凑 JSON
+func writeFlowJSON(w io.Writer, v interface{}) {
+	switch v := v.(type) {
+	case *orderedMap:
+		io.WriteString(w, "{")
+		for i, key := range v.keys {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			writeJSONString(w, key)
+			io.WriteString(w, ":")
+			writeFlowJSON(w, v.values[key])
+		}
+		io.WriteString(w, "}")
+	case []interface{}:
+		io.WriteString(w, "[")
+		for i, item := range v {
+			if i > 0 {
+				io.WriteString(w, ",")
+			}
+			writeFlowJSON(w, item)
+		}
+		io.WriteString(w, "]")
+	case string:
+		writeJSONString(w, v)
+	case nil:
+		io.WriteString(w, "null")
+	default:
+		fmt.Fprint(w, v)
+	}
+}
+
+func writeJSONString(w io.Writer, s string) {
+	io.WriteString(w, quoteString(s))
+}
+
+// quoteString 将 s 编码为 JSON 字符串，与 JSON 输出一致不转义 HTML 字符
+func quoteString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// plainYAMLString 不需要加引号的 YAML 字符串
+var plainYAMLString = regexp.MustCompile(`^[\p{L}_/][\p{L}\p{N}_\-./ ]*$`)
+
+// yamlString 输出 YAML 字符串，必要时使用双引号（与 JSON 字符串语法兼容）
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "", "null", "~", "true", "false", "yes", "no", "on", "off", "y", "n":
+		return quoteString(s)
+	}
+	if plainYAMLString.MatchString(s) && !strings.HasSuffix(s, " ") {
+		return s
+	}
+	return quoteString(s)
+}
+
+// writeYAML 以块格式输出 YAML
+func writeYAML(w io.Writer, v interface{}, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch v := v.(type) {
+	case *orderedMap:
+		if len(v.keys) == 0 {
+			io.WriteString(w, pad+"{}\n")
+			return
+		}
+		for _, key := range v.keys {
+			writeYAMLEntry(w, pad+yamlString(key)+":", v.values[key], indent)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			io.WriteString(w, pad+"[]\n")
+			return
+		}
+		for _, item := range v {
+			writeYAMLItem(w, pad, item, indent)
+		}
+	default:
+		io.WriteString(w, pad+yamlScalar(v)+"\n")
+	}
+}
+
+// writeYAMLEntry 输出对象的一个字段
+func writeYAMLEntry(w io.Writer, prefix string, value interface{}, indent int) {
+	switch value := value.(type) {
+	case *orderedMap:
+		if len(value.keys) == 0 {
+			io.WriteString(w, prefix+" {}\n")
+			return
+		}
+		io.WriteString(w, prefix+"\n")
+		writeYAML(w, value, indent+1)
+	case []interface{}:
+		if len(value) == 0 {
+			io.WriteString(w, prefix+" []\n")
+			return
+		}
+		io.WriteString(w, prefix+"\n")
+		writeYAML(w, value, indent)
+	default:
+		io.WriteString(w, prefix+" "+yamlScalar(value)+"\n")
+	}
+}
+
+// writeYAMLItem 输出数组的一个元素
+func writeYAMLItem(w io.Writer, pad string, item interface{}, indent int) {
+	switch item := item.(type) {
+	case *orderedMap:
+		if len(item.keys) == 0 {
+			io.WriteString(w, pad+"- {}\n")
+			return
+		}
+		// 第一个字段与 "- " 同行，其余字段缩进对齐
+		for i, key := range item.keys {
+			prefix := pad + "  "
+			if i == 0 {
+				prefix = pad + "- "
+			}
+			writeYAMLEntry(w, prefix+yamlString(key)+":", item.values[key], indent+1)
+		}
+	case []interface{}:
+		if len(item) == 0 {
+			io.WriteString(w, pad+"- []\n")
+			return
+		}
+		io.WriteString(w, pad+"-\n")
+		writeYAML(w, item, indent+1)
+	default:
+		io.WriteString(w, pad+"- "+yamlScalar(item)+"\n")
+	}
+}
+
+// yamlScalar 输出标量
+func yamlScalar(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return yamlString(v)
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	}
+	return fmt.Sprint(v)
+}
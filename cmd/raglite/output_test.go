@@ -0,0 +1,191 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+// toYAML 按 CLI 的方式将 JSON 转换为 YAML
+func toYAML(t *testing.T, in string) string {
+	t.Helper()
+	node, err := orderedJSON(json.RawMessage(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	writeYAML(&buf, node, 0)
+	return buf.String()
+}
+
+func TestYAMLString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"中文标题", "中文标题"},
+		{"/api/v1/search", "/api/v1/search"},
+		{"e.g. this - that", "e.g. this - that"},
+		{"", `""`},
+
+		// 会被解析为其他类型的字符串
+		{"true", `"true"`},
+		{"Yes", `"Yes"`},
+		{"null", `"null"`},
+		{"~", `"~"`},
+		{"123", `"123"`},
+		{"1.5", `"1.5"`},
+		{".inf", `".inf"`},
+		{"2024-01-01", `"2024-01-01"`},
+
+		// YAML 语法字符
+		{"key: value", `"key: value"`},
+		{"a:b", `"a:b"`},
+		{"# comment", `"# comment"`},
+		{"title #1", `"title #1"`},
+		{"- item", `"- item"`},
+		{"-", `"-"`},
+		{"---", `"---"`},
+		{"? key", `"? key"`},
+		{"@user", `"@user"`},
+		{"*alias", `"*alias"`},
+		{"&anchor", `"&anchor"`},
+		{"!tag", `"!tag"`},
+		{"%YAML", `"%YAML"`},
+		{"[1]", `"[1]"`},
+		{"{a}", `"{a}"`},
+		{"a, b", `"a, b"`},
+		{"|", `"|"`},
+		{">", `">"`},
+		{"it's", `"it's"`},
+		{`say "hi"`, `"say \"hi\""`},
+
+		// 空白和控制字符
+		{"line1\nline2", `"line1\nline2"`},
+		{"a\tb", `"a\tb"`},
+		{" leading", `" leading"`},
+		{"trailing ", `"trailing "`},
+	}
+	for _, tt := range tests {
+		if got := yamlString(tt.in); got != tt.want {
+			t.Errorf("yamlString(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWriteYAMLGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "scalars keep field order",
+			in:   `{"name":"docs","id":"ds-1","chunk_size":512,"score":0.875,"active":true,"parent":null,"total":12345678901234567890}`,
+			want: `name: docs
+id: ds-1
+chunk_size: 512
+score: 0.875
+active: true
+parent: null
+total: 12345678901234567890
+`,
+		},
+		{
+			name: "quoted values and keys",
+			in:   `{"title":"FAQ: billing","note":"# not a comment","content":"line1\nline2","bullet":"- item","x: y":"key with colon","":"empty key"}`,
+			want: `title: "FAQ: billing"
+note: "# not a comment"
+content: "line1\nline2"
+bullet: "- item"
+"x: y": key with colon
+"": empty key
+`,
+		},
+		{
+			name: "nested objects and lists",
+			in:   `{"dataset":{"id":"ds-1","config":{"chunk_size":512}},"tags":["a","b: c"],"results":[{"id":"c1","meta":{"page":1}},{"id":"c2","tags":["x"]}]}`,
+			want: `dataset:
+  id: ds-1
+  config:
+    chunk_size: 512
+tags:
+- a
+- "b: c"
+results:
+- id: c1
+  meta:
+    page: 1
+- id: c2
+  tags:
+  - x
+`,
+		},
+		{
+			name: "empty collections",
+			in:   `{"metadata":{},"tags":[],"items":[{},[],[["x"]]]}`,
+			want: `metadata: {}
+tags: []
+items:
+- {}
+- []
+-
+  -
+    - x
+`,
+		},
+		{
+			name: "top-level list",
+			in:   `[{"deleted":["ds-1","-ds-2"]}]`,
+			want: `- deleted:
+  - ds-1
+  - "-ds-2"
+`,
+		},
+		{
+			name: "top-level scalar",
+			in:   `"ok: done"`,
+			want: `"ok: done"
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toYAML(t, tt.in); got != tt.want {
+				t.Errorf("YAML output:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCell(t *testing.T) {
+	long := ""
+	for i := 0; i < 100; i++ {
+		long += "字"
+	}
+	tests := []struct {
+		in, want string
+	}{
+		{"a\n  b\tc", "a b c"},
+		{long, long[:77*3] + "..."},
+	}
+	for _, tt := range tests {
+		if got := cell(tt.in); got != tt.want {
+			t.Errorf("cell(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestScalarText(t *testing.T) {
+	node, err := orderedJSON(json.RawMessage(`{"n":null,"s":"x","num":1.50,"b":false,"obj":{"z":1,"a":[true,"<b>"]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := node.(*orderedMap)
+	want := map[string]string{"n": "", "s": "x", "num": "1.50", "b": "false", "obj": `{"z":1,"a":[true,"<b>"]}`}
+	for key, w := range want {
+		if got := scalarText(m.values[key]); got != w {
+			t.Errorf("scalarText(%s) = %s, want %s", key, got, w)
+		}
+	}
+}
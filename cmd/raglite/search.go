@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func init() {
+	register("search", "retrieve chunks: search --dataset <id> <query>", search)
+	register("qa", "ask a question: qa --dataset <id> <question>", qa)
+	register("health", "check server health", health)
+}
+
+var searchColumns = []column[sdk.SearchResult]{
+	{"SCORE", func(r sdk.SearchResult) string { return fmt.Sprintf("%.3f", r.Score) }},
+	{"DOCUMENT", func(r sdk.SearchResult) string { return r.DocumentTitle }},
+	{"SECTION", func(r sdk.SearchResult) string { return r.SectionTitle }},
+	{"CONTENT", func(r sdk.SearchResult) string { return r.Content }},
+}
+
+func search(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("search")
+	datasetID := datasetFlag(fs)
+	topK := fs.Int("top-k", 10, "number of results")
+	mode := fs.String("mode", "", "retrieval mode: full or smart")
+	threshold := fs.Float64("threshold", 0, "similarity threshold between 0 and 1")
+	tags := fs.String("tags", "", "comma separated tags to filter")
+	metadataJSON := fs.String("metadata", "", "metadata filter as a JSON object")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected <query>")
+	}
+	metadata, err := parseMetadata(*metadataJSON)
+	if err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Search.Retrieve(ctx, &sdk.RetrieveRequest{
+		Query:               strings.Join(args, " "),
+		DatasetID:           *datasetID,
+		TopK:                *topK,
+		RetrievalMode:       *mode,
+		SimilarityThreshold: *threshold,
+		Tags:                splitList(*tags),
+		Metadata:            metadata,
+	})
+	if err != nil {
+		return err
+	}
+	return printList(a, resp, resp.Results, searchColumns)
+}
+
+func qa(ctx context.Context, a *app, args []string) error {
+	fs := a.flagSet("qa")
+	datasetID := datasetFlag(fs)
+	topK := fs.Int("top-k", 10, "number of chunks to retrieve")
+	mode := fs.String("mode", "", "retrieval mode: full or smart")
+	threshold := fs.Float64("threshold", 0, "similarity threshold between 0 and 1")
+	stream := fs.Bool("stream", false, "print the answer as it is generated (table output only)")
+	showContext := fs.Bool("show-context", false, "print the retrieved context (table output only)")
+	args, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := requireDataset(*datasetID); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return fmt.Errorf("expected <question>")
+	}
+
+	cfg, err := a.global.resolve()
+	if err != nil {
+		return err
+	}
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+
+	req := &sdk.QARequest{
+		Query:               strings.Join(args, " "),
+		DatasetID:           *datasetID,
+		TopK:                *topK,
+		RetrievalMode:       *mode,
+		SimilarityThreshold: *threshold,
+	}
+
+	if !*stream || cfg.Output != "table" {
+		resp, err := client.QA.Ask(ctx, req)
+		if err != nil {
+			return err
+		}
+		if cfg.Output != "table" {
+			return a.encode(resp)
+		}
+		fmt.Fprintln(a.stdout, resp.Answer)
+		if *showContext {
+			fmt.Fprintln(a.stdout)
+			return printList(a, resp, resp.Context, searchColumns)
+		}
+		return nil
+	}
+
+	s, err := client.QA.AskStream(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	var contexts []sdk.SearchResult
+	for s.Next() {
+		ev := s.Current()
+		switch ev.Type {
+		case sdk.QAEventAnswer:
+			fmt.Fprint(a.stdout, ev.Delta)
+		case sdk.QAEventDone:
+			fmt.Fprintln(a.stdout)
+			contexts = ev.Context
+		}
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	if *showContext {
+		fmt.Fprintln(a.stdout)
+		return printList(a, contexts, contexts, searchColumns)
+	}
+	return nil
+}
+
+func health(ctx context.Context, a *app, args []string) error {
+	if _, err := parse(a.flagSet("health"), args); err != nil {
+		return err
+	}
+
+	client, err := a.sdkClient()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Health.Check(ctx)
+	if err != nil {
+		return err
+	}
+	return a.printObject(resp)
+}
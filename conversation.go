@@ -0,0 +1,295 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"unicode"
+)
+
+// Conversation 多轮对话会话
+//
+// 会话记录用户和助手的消息，并在检索和问答时作为 ChatHistory 传给服务端。
+// 历史按 MaxTurns 和 MaxTokens 自动裁剪，可以通过 json.Marshal 保存，再用 Client.RestoreConversation 恢复。
+// Conversation 是并发安全的，但同一会话的多个问答请求并发执行时消息顺序不确定。
+type Conversation struct {
+	client *Client
+	mu     sync.Mutex
+
+	// 默认数据集，请求中未指定 DatasetID 时使用
+	datasetID string
+
+	// 保留的最近轮数（一问一答为一轮），0 表示不限制
+	maxTurns int
+
+	// 历史消息的估算 token 上限，0 表示不限制
+	maxTokens int
+
+	// 历史消息，按时间顺序排列
+	messages []ChatMessage
+}
+
+// ConversationOptions 会话选项
+type ConversationOptions struct {
+	// 保留的最近轮数（一问一答为一轮），0 表示不限制
+	MaxTurns int
+
+	// 历史消息的估算 token 上限，0 表示不限制
+	MaxTokens int
+}
+
+// NewConversation 创建多轮对话会话
+func (c *Client) NewConversation(datasetID string, opts *ConversationOptions) *Conversation {
+	conv := &Conversation{
+		client:    c,
+		datasetID: datasetID,
+	}
+	if opts != nil {
+		conv.maxTurns = opts.MaxTurns
+		conv.maxTokens = opts.MaxTokens
+	}
+	return conv
+}
+
+// RestoreConversation 从 json.Marshal 保存的数据恢复会话
+func (c *Client) RestoreConversation(data []byte) (*Conversation, error) {
+	conv := &Conversation{client: c}
+	if err := conv.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// conversationState 会话的 JSON 表示
+type conversationState struct {
+	DatasetID string        `json:"dataset_id"`
+	MaxTurns  int           `json:"max_turns,omitempty"`
+	MaxTokens int           `json:"max_tokens,omitempty"`
+	Messages  []ChatMessage `json:"messages"`
+}
+
+// MarshalJSON 实现 json.Marshaler，保存时持有锁以免与进行中的问答冲突
+func (cv *Conversation) MarshalJSON() ([]byte, error) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	return json.Marshal(conversationState{
+		DatasetID: cv.datasetID,
+		MaxTurns:  cv.maxTurns,
+		MaxTokens: cv.maxTokens,
+		Messages:  cv.messages,
+	})
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler，用保存的数据替换会话的设置和历史，保留关联的客户端
+//
+// 零值的 Conversation 没有关联客户端，不能用于问答，应使用 Client.RestoreConversation 恢复。
+func (cv *Conversation) UnmarshalJSON(data []byte) error {
+	var state conversationState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return fmt.Errorf("failed to unmarshal conversation: %w", err)
+	}
+
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.datasetID = state.DatasetID
+	cv.maxTurns = state.MaxTurns
+	cv.maxTokens = state.MaxTokens
+	cv.messages = state.Messages
+	cv.trimLocked()
+	return nil
+}
+
+// DatasetID 返回默认数据集，请求中未指定 DatasetID 时使用
+func (cv *Conversation) DatasetID() string {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	return cv.datasetID
+}
+
+// SetDatasetID 设置默认数据集
+func (cv *Conversation) SetDatasetID(datasetID string) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.datasetID = datasetID
+}
+
+// MaxTurns 返回保留的最近轮数，0 表示不限制
+func (cv *Conversation) MaxTurns() int {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	return cv.maxTurns
+}
+
+// SetMaxTurns 设置保留的最近轮数（一问一答为一轮）并裁剪历史，0 表示不限制
+func (cv *Conversation) SetMaxTurns(maxTurns int) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.maxTurns = maxTurns
+	cv.trimLocked()
+}
+
+// MaxTokens 返回历史消息的估算 token 上限，0 表示不限制
+func (cv *Conversation) MaxTokens() int {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	return cv.maxTokens
+}
+
+// SetMaxTokens 设置历史消息的估算 token 上限并裁剪历史，0 表示不限制
+func (cv *Conversation) SetMaxTokens(maxTokens int) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.maxTokens = maxTokens
+	cv.trimLocked()
+}
+
+// History 返回历史消息的副本，按时间顺序排列
+func (cv *Conversation) History() []ChatMessage {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	return append([]ChatMessage(nil), cv.messages...)
+}
+
+// Append 追加消息并裁剪历史，用于记录在会话之外生成的回复
+func (cv *Conversation) Append(messages ...ChatMessage) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.messages = append(cv.messages, messages...)
+	cv.trimLocked()
+}
+
+// Reset 清空历史消息
+func (cv *Conversation) Reset() {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+	cv.messages = nil
+}
+
+// Retrieve 携带历史消息进行召回，不会记录消息
+func (cv *Conversation) Retrieve(ctx context.Context, req *RetrieveRequest, opts ...CallOption) (*SearchResponse, error) {
+	if req == nil {
+		return nil, errNilRequest()
+	}
+	r := *req
+	if r.DatasetID == "" {
+		r.DatasetID = cv.DatasetID()
+	}
+	r.ChatHistory = cv.History()
+	return cv.client.Search.Retrieve(ctx, &r, opts...)
+}
+
+// Ask 携带历史消息提问，成功后将问题和答案记录到历史中
+func (cv *Conversation) Ask(ctx context.Context, req *QARequest, opts ...CallOption) (*QAResponse, error) {
+	if req == nil {
+		return nil, errNilRequest()
+	}
+	r := cv.qaRequest(req)
+	resp, err := cv.client.QA.Ask(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	cv.Append(
+		ChatMessage{Role: ChatRoleUser, Content: r.Query},
+		ChatMessage{Role: ChatRoleAssistant, Content: resp.Answer},
+	)
+	return resp, nil
+}
+
+// AskStream 携带历史消息以流式方式提问，流正常结束时将问题和完整答案记录到历史中
+func (cv *Conversation) AskStream(ctx context.Context, req *QARequest, opts ...CallOption) (*QAStream, error) {
+	if req == nil {
+		return nil, errNilRequest()
+	}
+	r := cv.qaRequest(req)
+	stream, err := cv.client.QA.AskStream(ctx, r, opts...)
+	if err != nil {
+		return nil, err
+	}
+	stream.decoder = &conversationDecoder{
+		streamDecoder: stream.decoder,
+		onDone: func(answer string) {
+			cv.Append(
+				ChatMessage{Role: ChatRoleUser, Content: r.Query},
+				ChatMessage{Role: ChatRoleAssistant, Content: answer},
+			)
+		},
+	}
+	return stream, nil
+}
+
+// qaRequest 复制请求并填入默认数据集和历史消息
+func (cv *Conversation) qaRequest(req *QARequest) *QARequest {
+	r := *req
+	if r.DatasetID == "" {
+		r.DatasetID = cv.DatasetID()
+	}
+	r.ChatHistory = cv.History()
+	return &r
+}
+
+// trimLocked 按轮数和 token 上限裁剪历史，调用时需持有锁
+func (cv *Conversation) trimLocked() {
+	if cv.maxTurns > 0 && len(cv.messages) > cv.maxTurns*2 {
+		cv.messages = cv.messages[len(cv.messages)-cv.maxTurns*2:]
+	}
+
+	if cv.maxTokens > 0 {
+		total := 0
+		for _, m := range cv.messages {
+			total += estimateTokens(m.Content)
+		}
+		drop := 0
+		for drop < len(cv.messages) && total > cv.maxTokens {
+			total -= estimateTokens(cv.messages[drop].Content)
+			drop++
+		}
+		cv.messages = cv.messages[drop:]
+	}
+
+	// 避免历史以助手消息开头
+	for len(cv.messages) > 0 && cv.messages[0].Role == ChatRoleAssistant {
+		cv.messages = cv.messages[1:]
+	}
+	cv.messages = append([]ChatMessage(nil), cv.messages...)
+}
+
+// estimateTokens 粗略估算 token 数：CJK 字符按每字 1 个，其他字符按每 4 个 1 个
+func estimateTokens(s string) int {
+	cjk, other := 0, 0
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+			cjk++
+		} else {
+			other++
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+// conversationDecoder 在流结束时记录完整答案
+type conversationDecoder struct {
+	streamDecoder[QAStreamEvent]
+	onDone func(answer string)
+}
+
+func (d *conversationDecoder) decode(ev *streamEvent) ([]QAStreamEvent, error) {
+	events, err := d.streamDecoder.decode(ev)
+	d.record(events)
+	return events, err
+}
+
+func (d *conversationDecoder) finish(terminated bool) ([]QAStreamEvent, error) {
+	events, err := d.streamDecoder.finish(terminated)
+	d.record(events)
+	return events, err
+}
+
+func (d *conversationDecoder) record(events []QAStreamEvent) {
+	for _, ev := range events {
+		if ev.Type == QAEventDone && d.onDone != nil {
+			d.onDone(ev.Answer)
+			d.onDone = nil
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestConversationForwardsCallOptions(t *testing.T) {
+	srv := newScriptedServer(t)
+	srv.successful = `{"success":true,"data":{"answer":"RAGLite","context":[]}}`
+	client := newTestClient(t, srv.URL)
+	conv := client.NewConversation("ds-1", nil)
+
+	var meta ResponseMeta
+	_, err := conv.Ask(context.Background(), &QARequest{Query: "what?"},
+		WithHeader("X-Tenant", "acme"), WithRequestID("req-1"), WithResponseMeta(&meta))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := srv.received()[0]
+	if got := r.Header.Get("X-Tenant"); got != "acme" {
+		t.Errorf("X-Tenant = %q, want acme", got)
+	}
+	if meta.RequestID != "req-1" {
+		t.Errorf("ResponseMeta.RequestID = %q, want req-1", meta.RequestID)
+	}
+	if got := len(conv.History()); got != 2 {
+		t.Errorf("history = %d messages, want 2", got)
+	}
+}
+
+func TestConversationNilRequest(t *testing.T) {
+	conv := newTestClient(t, "http://raglite.test").NewConversation("ds-1", nil)
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"Retrieve":  func() error { _, err := conv.Retrieve(ctx, nil); return err },
+		"Ask":       func() error { _, err := conv.Ask(ctx, nil); return err },
+		"AskStream": func() error { _, err := conv.AskStream(ctx, nil); return err },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s(nil) error = %v, want ErrValidation", name, err)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CredentialsProvider 在每次请求（包括重试）前提供认证令牌，通过 WithCredentials 配置
+//
+// 返回的令牌以 Authorization: Bearer <token> 发送，空字符串表示不认证。实现需要并发安全。
+type CredentialsProvider interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// CredentialsRefresher 可以刷新令牌的 CredentialsProvider
+//
+// 请求返回 401 时客户端调用 Refresh 并使用新的令牌重试一次。
+// rejected 为被拒绝的令牌，并发请求同时收到 401 时实现可以据此避免重复刷新。
+type CredentialsRefresher interface {
+	CredentialsProvider
+	Refresh(ctx context.Context, rejected string) error
+}
+
+// StaticCredentials 固定的 API Key，与 WithAPIKey 相同
+type StaticCredentials string
+
+// Token 实现 CredentialsProvider
+func (s StaticCredentials) Token(ctx context.Context) (string, error) {
+	return string(s), nil
+}
+
+// EnvCredentials 每次请求时从指定的环境变量读取 API Key，值为环境变量名
+type EnvCredentials string
+
+// Token 实现 CredentialsProvider
+func (e EnvCredentials) Token(ctx context.Context) (string, error) {
+	return strings.TrimSpace(os.Getenv(string(e))), nil
+}
+
+// FileCredentials 从文件读取 API Key，文件的修改时间或大小变化时重新读取，
+// 适用于 Kubernetes Secret 等会被轮换的挂载文件
+type FileCredentials struct {
+	path string
+
+	mu      sync.Mutex
+	token   string
+	modTime time.Time
+	size    int64
+	loaded  bool
+}
+
+// NewFileCredentials 创建 FileCredentials，首尾空白会被忽略
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{path: path}
+}
+
+// Token 实现 CredentialsProvider
+func (f *FileCredentials) Token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to stat credentials file: %w", err)
+	}
+	if f.loaded && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return f.token, nil
+	}
+	return f.loadLocked(info)
+}
+
+// Refresh 实现 CredentialsRefresher，强制重新读取文件
+func (f *FileCredentials) Refresh(ctx context.Context, rejected string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return fmt.Errorf("failed to stat credentials file: %w", err)
+	}
+	_, err = f.loadLocked(info)
+	return err
+}
+
+func (f *FileCredentials) loadLocked(info os.FileInfo) (string, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read credentials file: %w", err)
+	}
+	f.token = strings.TrimSpace(string(data))
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	f.loaded = true
+	return f.token, nil
+}
+
+// OAuth2Config OAuth2 client credentials 授权配置
+type OAuth2Config struct {
+	// 令牌端点，例如 https://auth.example.com/oauth2/token
+	TokenURL string
+
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+
+	// 额外的表单参数，例如 audience
+	EndpointParams url.Values
+
+	// 为 true 时在表单中发送 client_id 和 client_secret，否则使用 HTTP Basic 认证
+	AuthInParams bool
+
+	// 请求令牌使用的 HTTP 客户端，默认超时 30 秒
+	HTTPClient *http.Client
+}
+
+// tokenExpiryDelta 令牌在过期前多久被视为已过期，避免请求途中过期
+const tokenExpiryDelta = 30 * time.Second
+
+// OAuth2Credentials 通过 client credentials 授权获取访问令牌，令牌在过期前缓存复用
+type OAuth2Credentials struct {
+	config OAuth2Config
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// NewOAuth2Credentials 创建 OAuth2Credentials
+func NewOAuth2Credentials(config OAuth2Config) *OAuth2Credentials {
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &OAuth2Credentials{config: config}
+}
+
+// Token 实现 CredentialsProvider，令牌不存在或即将过期时重新获取
+func (o *OAuth2Credentials) Token(ctx context.Context) (string, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token != "" && (o.expires.IsZero() || time.Now().Before(o.expires)) {
+		return o.token, nil
+	}
+	if err := o.fetchLocked(ctx); err != nil {
+		return "", err
+	}
+	return o.token, nil
+}
+
+// Refresh 实现 CredentialsRefresher，缓存的令牌仍是被拒绝的令牌时重新获取
+func (o *OAuth2Credentials) Refresh(ctx context.Context, rejected string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.token != rejected {
+		// 其他请求已经刷新过
+		return nil
+	}
+	return o.fetchLocked(ctx)
+}
+
+// tokenResponse 令牌端点的响应
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int64  `json:"expires_in"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (o *OAuth2Credentials) fetchLocked(ctx context.Context) error {
+	form := url.Values{}
+	for k, v := range o.config.EndpointParams {
+		form[k] = v
+	}
+	form.Set("grant_type", "client_credentials")
+	if len(o.config.Scopes) > 0 {
+		form.Set("scope", strings.Join(o.config.Scopes, " "))
+	}
+	if o.config.AuthInParams {
+		form.Set("client_id", o.config.ClientID)
+		form.Set("client_secret", o.config.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, o.config.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if !o.config.AuthInParams {
+		req.SetBasicAuth(url.QueryEscape(o.config.ClientID), url.QueryEscape(o.config.ClientSecret))
+	}
+
+	resp, err := o.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to request token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return fmt.Errorf("failed to read token response: %w", err)
+	}
+
+	var tr tokenResponse
+	if err := json.Unmarshal(body, &tr); err != nil && resp.StatusCode == http.StatusOK {
+		return fmt.Errorf("failed to unmarshal token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK || tr.AccessToken == "" {
+		msg := firstNonEmpty(tr.ErrorDescription, tr.Error, strings.TrimSpace(string(body)), http.StatusText(resp.StatusCode))
+		return fmt.Errorf("failed to obtain token (status %d): %s", resp.StatusCode, msg)
+	}
+
+	o.token = tr.AccessToken
+	o.expires = time.Time{}
+	if tr.ExpiresIn > 0 {
+		o.expires = time.Now().Add(time.Duration(tr.ExpiresIn)*time.Second - tokenExpiryDelta)
+	}
+	return nil
+}
@@ -0,0 +1,300 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEnvCredentials(t *testing.T) {
+	const name = "RAGLITE_TEST_API_KEY"
+	creds := EnvCredentials(name)
+
+	t.Setenv(name, "  key-1\n")
+	if got, err := creds.Token(context.Background()); err != nil || got != "key-1" {
+		t.Errorf("Token() = %q, %v, want key-1", got, err)
+	}
+
+	// 每次请求重新读取
+	t.Setenv(name, "key-2")
+	if got, _ := creds.Token(context.Background()); got != "key-2" {
+		t.Errorf("Token() after change = %q, want key-2", got)
+	}
+
+	os.Unsetenv(name)
+	if got, err := creds.Token(context.Background()); err != nil || got != "" {
+		t.Errorf("Token() when unset = %q, %v, want no token", got, err)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-key")
+	write := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	creds := NewFileCredentials(path)
+
+	if _, err := creds.Token(ctx); err == nil || !strings.Contains(err.Error(), "failed to stat credentials file") {
+		t.Errorf("Token() for a missing file error = %v, want a stat error", err)
+	}
+
+	t0 := time.Now().Add(-time.Hour).Truncate(time.Second)
+	write("key-1\n", t0)
+	if got, err := creds.Token(ctx); err != nil || got != "key-1" {
+		t.Fatalf("Token() = %q, %v, want key-1", got, err)
+	}
+
+	// 修改时间和大小都未变化时使用缓存
+	write("key-2\n", t0)
+	if got, _ := creds.Token(ctx); got != "key-1" {
+		t.Errorf("Token() with unchanged mtime and size = %q, want the cached key-1", got)
+	}
+
+	// Refresh 强制重新读取
+	if err := creds.Refresh(ctx, "key-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := creds.Token(ctx); got != "key-2" {
+		t.Errorf("Token() after Refresh = %q, want key-2", got)
+	}
+
+	// 轮换后修改时间变化，自动重新读取
+	write("key-3\n", t0.Add(time.Minute))
+	if got, _ := creds.Token(ctx); got != "key-3" {
+		t.Errorf("Token() after rotation = %q, want key-3", got)
+	}
+}
+
+// tokenServer 模拟 OAuth2 令牌端点，依次签发 token-1、token-2……
+type tokenServer struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	forms     []url.Values
+	basicAuth []string
+	expiresIn string
+	status    int
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	t.Helper()
+	s := &tokenServer{expiresIn: "3600", status: http.StatusOK}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		user, pass, _ := r.BasicAuth()
+
+		s.mu.Lock()
+		s.forms = append(s.forms, r.PostForm)
+		s.basicAuth = append(s.basicAuth, user+":"+pass)
+		n := len(s.forms)
+		status, expiresIn := s.status, s.expiresIn
+		s.mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			io.WriteString(w, `{"error":"invalid_client","error_description":"client authentication failed"}`)
+			return
+		}
+		io.WriteString(w, `{"access_token":"token-`+string(rune('0'+n))+`","token_type":"Bearer","expires_in":`+expiresIn+`}`)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *tokenServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.forms)
+}
+
+func TestOAuth2Credentials(t *testing.T) {
+	srv := newTokenServer(t)
+	creds := NewOAuth2Credentials(OAuth2Config{
+		TokenURL:       srv.URL,
+		ClientID:       "client",
+		ClientSecret:   "s3cret",
+		Scopes:         []string{"search", "qa"},
+		EndpointParams: url.Values{"audience": {"raglite"}},
+	})
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if got, err := creds.Token(ctx); err != nil || got != "token-1" {
+			t.Fatalf("Token() call %d = %q, %v, want the cached token-1", i+1, got, err)
+		}
+	}
+	if got := srv.count(); got != 1 {
+		t.Errorf("token requests = %d, want 1", got)
+	}
+	form := srv.forms[0]
+	if form.Get("grant_type") != "client_credentials" || form.Get("scope") != "search qa" || form.Get("audience") != "raglite" {
+		t.Errorf("form = %v, want grant_type, scope and audience", form)
+	}
+	if form.Get("client_secret") != "" || srv.basicAuth[0] != "client:s3cret" {
+		t.Errorf("client authentication = form %v, basic %q, want HTTP Basic only", form, srv.basicAuth[0])
+	}
+
+	// 其他请求已经刷新过时不再重复获取
+	if err := creds.Refresh(ctx, "stale-token"); err != nil || srv.count() != 1 {
+		t.Errorf("Refresh(stale) = %v with %d token requests, want no new request", err, srv.count())
+	}
+	if err := creds.Refresh(ctx, "token-1"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := creds.Token(ctx); got != "token-2" || srv.count() != 2 {
+		t.Errorf("Token() after Refresh = %q with %d token requests, want token-2", got, srv.count())
+	}
+}
+
+func TestOAuth2CredentialsExpiry(t *testing.T) {
+	srv := newTokenServer(t)
+	// 有效期短于 tokenExpiryDelta，每次都视为已过期
+	srv.expiresIn = "10"
+	creds := NewOAuth2Credentials(OAuth2Config{TokenURL: srv.URL, ClientID: "client", ClientSecret: "s3cret", AuthInParams: true})
+
+	for _, want := range []string{"token-1", "token-2"} {
+		if got, err := creds.Token(context.Background()); err != nil || got != want {
+			t.Errorf("Token() = %q, %v, want %s", got, err, want)
+		}
+	}
+	if form := srv.forms[0]; form.Get("client_id") != "client" || form.Get("client_secret") != "s3cret" || srv.basicAuth[0] != ":" {
+		t.Errorf("form = %v, basic %q, want the client credentials in the form only", form, srv.basicAuth[0])
+	}
+}
+
+func TestOAuth2CredentialsError(t *testing.T) {
+	srv := newTokenServer(t)
+	srv.status = http.StatusUnauthorized
+	creds := NewOAuth2Credentials(OAuth2Config{TokenURL: srv.URL, ClientID: "client", ClientSecret: "wrong"})
+
+	_, err := creds.Token(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "status 401") || !strings.Contains(err.Error(), "client authentication failed") {
+		t.Errorf("Token() error = %v, want the token endpoint's error description", err)
+	}
+}
+
+// fakeRefresher 依次返回 tokens 中的令牌，每次 Refresh 前进一个
+type fakeRefresher struct {
+	mu         sync.Mutex
+	tokens     []string
+	refreshErr error
+	rejected   []string
+}
+
+func (f *fakeRefresher) Token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.tokens[0], nil
+}
+
+func (f *fakeRefresher) Refresh(ctx context.Context, rejected string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.rejected = append(f.rejected, rejected)
+	if f.refreshErr != nil {
+		return f.refreshErr
+	}
+	if len(f.tokens) > 1 {
+		f.tokens = f.tokens[1:]
+	}
+	return nil
+}
+
+func TestSendRefreshesOnUnauthorized(t *testing.T) {
+	errRefresh := errors.New("token endpoint down")
+	tests := []struct {
+		name         string
+		tokens       []string
+		refreshErr   error
+		wantRequests int
+		wantRefresh  []string
+		wantErr      error
+	}{
+		{"refresh once and retry", []string{"old", "new"}, nil, 2, []string{"old"}, nil},
+		{"still rejected after refresh", []string{"old", "older"}, nil, 2, []string{"old"}, ErrUnauthorized},
+		{"refresh fails", []string{"old", "new"}, errRefresh, 1, []string{"old"}, ErrUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newScriptedServer(t)
+			srv.apiKey = "new"
+			creds := &fakeRefresher{tokens: tt.tokens, refreshErr: tt.refreshErr}
+			client := newTestClient(t, srv.URL, WithCredentials(creds), WithRetryPolicy(fastRetryPolicy(3)))
+
+			err := getDataset(context.Background(), client)
+			if tt.wantErr == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.refreshErr != nil && (!strings.Contains(err.Error(), "failed to refresh credentials") || !strings.Contains(err.Error(), tt.refreshErr.Error())) {
+				t.Errorf("err = %v, want the refresh failure mentioned", err)
+			}
+			if got := srv.count(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if len(creds.rejected) != len(tt.wantRefresh) || creds.rejected[0] != tt.wantRefresh[0] {
+				t.Errorf("Refresh calls = %q, want %q", creds.rejected, tt.wantRefresh)
+			}
+		})
+	}
+}
+
+func TestSendDoesNotRefreshStaticCredentials(t *testing.T) {
+	srv := newScriptedServer(t)
+	srv.apiKey = "right"
+	client := newTestClient(t, srv.URL, WithAPIKey("wrong"), WithRetryPolicy(fastRetryPolicy(3)))
+
+	if err := getDataset(context.Background(), client); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("err = %v, want ErrUnauthorized", err)
+	}
+	if got := srv.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestSendRefreshWithUnreplayableUpload(t *testing.T) {
+	srv := newScriptedServer(t)
+	srv.apiKey = "new"
+	srv.successful = `{"success":true,"data":{"document_id":"doc-1"}}`
+	creds := &fakeRefresher{tokens: []string{"old", "new"}}
+	client := newTestClient(t, srv.URL, WithCredentials(creds))
+
+	_, err := client.Documents.Upload(context.Background(), &UploadDocumentRequest{
+		DatasetID: "ds-1",
+		Filename:  "a.txt",
+		File:      readOnly{strings.NewReader("file content")},
+	})
+	// 请求体已被读取，无法用新令牌重发，返回原来的 401
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("err = %v, want the original 401 *APIError", err)
+	}
+	if errors.Is(err, errBodyNotReplayable) {
+		t.Errorf("err = %v, want the replay failure hidden", err)
+	}
+	if got := srv.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if len(creds.rejected) != 1 {
+		t.Errorf("Refresh calls = %d, want 1", len(creds.rejected))
+	}
+}
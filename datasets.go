@@ -22,6 +22,17 @@ type CreateDatasetRequest struct {
 	Config          DatasetConfig `json:"config,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *CreateDatasetRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("name", r.Name)
+	v.datasetConfig("config.", &r.Config)
+	return v.err()
+}
+
 // UpdateDatasetRequest 更新数据集请求
 type UpdateDatasetRequest struct {
 	Name            *string        `json:"name,omitempty"`
@@ -35,9 +46,34 @@ type UpdateDatasetRequest struct {
 	Status          *string        `json:"status,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *UpdateDatasetRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.notEmpty("name", r.Name)
+	if r.Config != nil {
+		v.datasetConfig("config.", r.Config)
+	}
+	return v.err()
+}
+
 // ListDatasetsRequest 列表查询请求
 type ListDatasetsRequest struct {
-	Status string
+	Status   string
+	Page     int // 页码，默认 1
+	PageSize int // 每页数量，默认 20，设为 PageSizeAll 则不分页
+}
+
+// Validate 校验请求参数，nil 表示使用默认值
+func (r *ListDatasetsRequest) Validate() error {
+	if r == nil {
+		return nil
+	}
+	var v validation
+	v.page(r.Page, r.PageSize)
+	return v.err()
 }
 
 // ListDatasetsResponse 列表响应
@@ -49,9 +85,14 @@ type ListDatasetsResponse struct {
 }
 
 // Create 创建数据集
-func (s *DatasetsService) Create(ctx context.Context, req *CreateDatasetRequest) (*Dataset, error) {
+func (s *DatasetsService) Create(ctx context.Context, req *CreateDatasetRequest, opts ...CallOption) (*Dataset, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result Dataset
-	err := s.client.do(ctx, "POST", "/api/v1/datasets", req, &result)
+	op := &Operation{Service: "Datasets", Method: "Create", HTTPMethod: "POST", Path: "/api/v1/datasets", Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -59,26 +100,57 @@ func (s *DatasetsService) Create(ctx context.Context, req *CreateDatasetRequest)
 }
 
 // List 列出数据集
-func (s *DatasetsService) List(ctx context.Context, req *ListDatasetsRequest) (*ListDatasetsResponse, error) {
+func (s *DatasetsService) List(ctx context.Context, req *ListDatasetsRequest, opts ...CallOption) (*ListDatasetsResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	params := make(map[string]string)
-	if req != nil && req.Status != "" {
-		params["status"] = req.Status
+	if req != nil {
+		if req.Status != "" {
+			params["status"] = req.Status
+		}
+		setPageParams(params, req.Page, req.PageSize)
 	}
 
 	path := s.client.buildURL("/api/v1/datasets", params)
 	var result ListDatasetsResponse
-	err := s.client.do(ctx, "GET", path, nil, &result)
+	op := &Operation{Service: "Datasets", Method: "List", HTTPMethod: "GET", Path: path, Request: req}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
+// ListPager 返回逐页列出数据集的迭代器，req 可以为 nil，其中的 Page 和 PageSize 会被忽略，callOpts 作用于每一页的请求
+func (s *DatasetsService) ListPager(req *ListDatasetsRequest, opts *PagerOptions, callOpts ...CallOption) *Pager[Dataset] {
+	return NewPager(func(ctx context.Context, page, pageSize int) ([]Dataset, int64, error) {
+		var pageReq ListDatasetsRequest
+		if req != nil {
+			pageReq = *req
+		}
+		pageReq.Page = page
+		pageReq.PageSize = pageSize
+
+		resp, err := s.List(ctx, &pageReq, callOpts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Datasets, resp.Total, nil
+	}, opts)
+}
+
 // Get 获取数据集详情
-func (s *DatasetsService) Get(ctx context.Context, datasetID string) (*Dataset, error) {
+func (s *DatasetsService) Get(ctx context.Context, datasetID string, opts ...CallOption) (*Dataset, error) {
+	if err := s.client.validateIDs("dataset_id", datasetID); err != nil {
+		return nil, err
+	}
+
 	var result Dataset
 	path := fmt.Sprintf("/api/v1/datasets/%s", datasetID)
-	err := s.client.do(ctx, "GET", path, nil, &result)
+	op := &Operation{Service: "Datasets", Method: "Get", HTTPMethod: "GET", Path: path, DatasetID: datasetID}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -86,10 +158,18 @@ func (s *DatasetsService) Get(ctx context.Context, datasetID string) (*Dataset,
 }
 
 // Update 更新数据集
-func (s *DatasetsService) Update(ctx context.Context, datasetID string, req *UpdateDatasetRequest) (*Dataset, error) {
+func (s *DatasetsService) Update(ctx context.Context, datasetID string, req *UpdateDatasetRequest, opts ...CallOption) (*Dataset, error) {
+	if err := s.client.validateIDs("dataset_id", datasetID); err != nil {
+		return nil, err
+	}
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result Dataset
 	path := fmt.Sprintf("/api/v1/datasets/%s", datasetID)
-	err := s.client.do(ctx, "PUT", path, req, &result)
+	op := &Operation{Service: "Datasets", Method: "Update", HTTPMethod: "PUT", Path: path, DatasetID: datasetID, Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -97,16 +177,26 @@ func (s *DatasetsService) Update(ctx context.Context, datasetID string, req *Upd
 }
 
 // Delete 删除数据集
-func (s *DatasetsService) Delete(ctx context.Context, datasetID string) error {
+func (s *DatasetsService) Delete(ctx context.Context, datasetID string, opts ...CallOption) error {
+	if err := s.client.validateIDs("dataset_id", datasetID); err != nil {
+		return err
+	}
+
 	path := fmt.Sprintf("/api/v1/datasets/%s", datasetID)
-	return s.client.do(ctx, "DELETE", path, nil, nil)
+	op := &Operation{Service: "Datasets", Method: "Delete", HTTPMethod: "DELETE", Path: path, DatasetID: datasetID}
+	return s.client.do(ctx, op, nil, nil, opts...)
 }
 
 // GetStats 获取数据集统计信息
-func (s *DatasetsService) GetStats(ctx context.Context, datasetID string) (*DatasetStats, error) {
+func (s *DatasetsService) GetStats(ctx context.Context, datasetID string, opts ...CallOption) (*DatasetStats, error) {
+	if err := s.client.validateIDs("dataset_id", datasetID); err != nil {
+		return nil, err
+	}
+
 	var result DatasetStats
 	path := fmt.Sprintf("/api/v1/datasets/%s/stats", datasetID)
-	err := s.client.do(ctx, "GET", path, nil, &result)
+	op := &Operation{Service: "Datasets", Method: "GetStats", HTTPMethod: "GET", Path: path, DatasetID: datasetID}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,392 @@
+package sdk
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SyncPathMetadataKey 同步时写入文档 metadata 的相对路径，用于匹配本地文件和远端文档
+const SyncPathMetadataKey = "sync_path"
+
+// syncDeleteBatchSize 同步删除时每次 BatchDelete 的最大文档数
+const syncDeleteBatchSize = 100
+
+// 同步动作类型
+const (
+	SyncActionCreate    = "create"    // 本地新增，上传
+	SyncActionReplace   = "replace"   // 内容变化，通过 DocumentID 替换
+	SyncActionDelete    = "delete"    // 本地已删除，删除远端文档
+	SyncActionUnchanged = "unchanged" // 内容未变化
+)
+
+// SyncOptions 目录同步选项
+type SyncOptions struct {
+	// 只生成同步计划，不做任何修改
+	DryRun bool
+
+	// 删除本地已不存在的远端文档，默认保留；数据集中不是由同步上传的文档同样会被删除
+	Delete bool
+
+	// 过滤文件和目录，返回 false 时跳过（目录会整体跳过），默认跳过以 "." 开头的文件和目录
+	Filter func(relPath string, d fs.DirEntry) bool
+
+	// 根据相对路径生成文档的 tags 和 metadata，默认不设置
+	Mapper func(relPath string) (tags []string, metadata map[string]interface{})
+
+	// 计算本地文件哈希的算法，默认根据远端 FileHash 的长度推断（MD5/SHA-1/SHA-256）
+	Hash func() hash.Hash
+
+	// 上传选项，OnResult 会被同步过程占用
+	Upload *BulkUploadOptions
+}
+
+// SyncAction 单个同步动作
+type SyncAction struct {
+	Type string
+
+	// 本地文件的相对路径（以 "/" 分隔），删除动作时为远端文档的路径
+	Path string
+
+	// 远端文档 ID，新增时在同步完成后填入
+	DocumentID string
+
+	Size int64
+
+	// 执行失败时的错误
+	Err error
+}
+
+// SyncPlan 同步计划及执行结果
+type SyncPlan struct {
+	DatasetID string
+	DryRun    bool
+	Actions   []SyncAction
+}
+
+// Count 返回指定类型的动作数量
+func (p *SyncPlan) Count(actionType string) int {
+	n := 0
+	for _, action := range p.Actions {
+		if action.Type == actionType {
+			n++
+		}
+	}
+	return n
+}
+
+// String 以 diff 风格输出计划，便于 dry-run 时查看
+func (p *SyncPlan) String() string {
+	var b strings.Builder
+	for _, action := range p.Actions {
+		var mark string
+		switch action.Type {
+		case SyncActionCreate:
+			mark = "+"
+		case SyncActionReplace:
+			mark = "~"
+		case SyncActionDelete:
+			mark = "-"
+		default:
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s", mark, action.Path)
+		if action.DocumentID != "" {
+			fmt.Fprintf(&b, " (%s)", action.DocumentID)
+		}
+		if action.Err != nil {
+			fmt.Fprintf(&b, ": %v", action.Err)
+		}
+		b.WriteByte('\n')
+	}
+	fmt.Fprintf(&b, "%d to create, %d to replace, %d to delete, %d unchanged\n",
+		p.Count(SyncActionCreate), p.Count(SyncActionReplace), p.Count(SyncActionDelete), p.Count(SyncActionUnchanged))
+	return b.String()
+}
+
+// SyncDir 将本地目录同步到数据集，见 SyncFS
+func (s *DocumentsService) SyncDir(ctx context.Context, datasetID, dir string, opts *SyncOptions, callOpts ...CallOption) (*SyncPlan, error) {
+	return s.SyncFS(ctx, datasetID, os.DirFS(dir), opts, callOpts...)
+}
+
+// SyncFS 将文件系统中的文件同步到数据集
+//
+// 本地文件以相对路径与远端文档匹配（优先使用 metadata 中的 sync_path，其次是 Filename）：
+// 远端不存在的文件会被上传，FileHash 不一致的文件会通过 DocumentID 替换，
+// 本地不存在的远端文档默认保留，Delete 为 true 时删除。
+// 远端文档没有 FileHash 时按文件大小判断是否变化。
+//
+// 返回的计划包含每个动作的执行结果，有动作失败时同时返回汇总的错误。
+// callOpts 作用于同步过程中的每一个请求，上传时的处理见 UploadMany。
+func (s *DocumentsService) SyncFS(ctx context.Context, datasetID string, fsys fs.FS, opts *SyncOptions, callOpts ...CallOption) (*SyncPlan, error) {
+	if opts == nil {
+		opts = &SyncOptions{}
+	}
+
+	plan, err := s.planSync(ctx, datasetID, fsys, opts, callOpts)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return plan, nil
+	}
+	return plan, s.applySync(ctx, plan, fsys, opts, callOpts)
+}
+
+// planSync 对比本地文件和远端文档，生成同步计划
+func (s *DocumentsService) planSync(ctx context.Context, datasetID string, fsys fs.FS, opts *SyncOptions, callOpts []CallOption) (*SyncPlan, error) {
+	remote := make(map[string]Document)
+	var duplicates []Document
+
+	pager := s.ListPager(&ListDocumentsRequest{DatasetID: datasetID}, &PagerOptions{PageSize: 100}, callOpts...)
+	for pager.Next(ctx) {
+		doc := pager.Current()
+		p := syncPath(&doc)
+		if _, ok := remote[p]; ok {
+			duplicates = append(duplicates, doc)
+			continue
+		}
+		remote[p] = doc
+	}
+	if err := pager.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list remote documents: %w", err)
+	}
+
+	filter := opts.Filter
+	if filter == nil {
+		filter = func(relPath string, d fs.DirEntry) bool {
+			return !strings.HasPrefix(d.Name(), ".")
+		}
+	}
+
+	plan := &SyncPlan{DatasetID: datasetID, DryRun: opts.DryRun}
+	seen := make(map[string]bool)
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		if !filter(p, d) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		seen[p] = true
+
+		doc, ok := remote[p]
+		if !ok {
+			plan.Actions = append(plan.Actions, SyncAction{Type: SyncActionCreate, Path: p, Size: info.Size()})
+			return nil
+		}
+
+		changed, err := fileChanged(fsys, p, info.Size(), &doc, opts.Hash)
+		if err != nil {
+			return err
+		}
+		actionType := SyncActionUnchanged
+		if changed {
+			actionType = SyncActionReplace
+		}
+		plan.Actions = append(plan.Actions, SyncAction{Type: actionType, Path: p, DocumentID: doc.ID, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan files: %w", err)
+	}
+
+	if opts.Delete {
+		var deletes []SyncAction
+		for p, doc := range remote {
+			if !seen[p] {
+				deletes = append(deletes, SyncAction{Type: SyncActionDelete, Path: p, DocumentID: doc.ID, Size: doc.FileSize})
+			}
+		}
+		for _, doc := range duplicates {
+			deletes = append(deletes, SyncAction{Type: SyncActionDelete, Path: syncPath(&doc), DocumentID: doc.ID, Size: doc.FileSize})
+		}
+		sort.Slice(deletes, func(i, j int) bool {
+			if deletes[i].Path != deletes[j].Path {
+				return deletes[i].Path < deletes[j].Path
+			}
+			return deletes[i].DocumentID < deletes[j].DocumentID
+		})
+		plan.Actions = append(plan.Actions, deletes...)
+	}
+	return plan, nil
+}
+
+// applySync 执行同步计划，结果写回 plan.Actions
+func (s *DocumentsService) applySync(ctx context.Context, plan *SyncPlan, fsys fs.FS, opts *SyncOptions, callOpts []CallOption) error {
+	uploadOpts := BulkUploadOptions{}
+	if opts.Upload != nil {
+		uploadOpts = *opts.Upload
+	}
+
+	// 上传请求的序号与动作的对应关系
+	var uploads []int
+	for i, action := range plan.Actions {
+		if action.Type == SyncActionCreate || action.Type == SyncActionReplace {
+			uploads = append(uploads, i)
+		}
+	}
+
+	reqs := make(chan *UploadDocumentRequest)
+	openErrs := make(map[int]error)
+	produced := make(chan struct{})
+	go func() {
+		defer close(produced)
+		defer close(reqs)
+		for _, i := range uploads {
+			action := &plan.Actions[i]
+			file, err := fsys.Open(action.Path)
+			if err != nil {
+				openErrs[i] = err
+				continue
+			}
+
+			req := &UploadDocumentRequest{
+				DatasetID:  plan.DatasetID,
+				DocumentID: action.DocumentID,
+				File:       file,
+				Filename:   action.Path,
+			}
+			var metadata map[string]interface{}
+			if opts.Mapper != nil {
+				req.Tags, metadata = opts.Mapper(action.Path)
+			}
+			req.Metadata = make(map[string]interface{}, len(metadata)+1)
+			for k, v := range metadata {
+				req.Metadata[k] = v
+			}
+			req.Metadata[SyncPathMetadataKey] = action.Path
+
+			select {
+			case reqs <- req:
+			case <-ctx.Done():
+				file.Close()
+				return
+			}
+		}
+	}()
+
+	// UploadMany 的序号只计入成功打开的文件，用文件名找回对应的动作
+	byPath := make(map[string]int, len(uploads))
+	for _, i := range uploads {
+		byPath[plan.Actions[i].Path] = i
+	}
+	uploadOpts.OnResult = func(result *BulkUploadResult) {
+		if closer, ok := result.Request.File.(io.Closer); ok {
+			closer.Close()
+		}
+		action := &plan.Actions[byPath[result.Request.Filename]]
+		action.Err = result.Err
+		if result.Response != nil && action.DocumentID == "" {
+			action.DocumentID = result.Response.DocumentID
+		}
+	}
+
+	_, err := s.UploadMany(ctx, reqs, &uploadOpts, callOpts...)
+	<-produced
+	for i, openErr := range openErrs {
+		plan.Actions[i].Err = fmt.Errorf("failed to open file: %w", openErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 删除远端多余的文档
+	var deletes []int
+	for i, action := range plan.Actions {
+		if action.Type == SyncActionDelete {
+			deletes = append(deletes, i)
+		}
+	}
+	for start := 0; start < len(deletes); start += syncDeleteBatchSize {
+		end := start + syncDeleteBatchSize
+		if end > len(deletes) {
+			end = len(deletes)
+		}
+
+		ids := make([]string, 0, end-start)
+		for _, i := range deletes[start:end] {
+			ids = append(ids, plan.Actions[i].DocumentID)
+		}
+		req := &BatchDeleteDocumentsRequest{DatasetID: plan.DatasetID, DocumentIDs: ids}
+		if err := s.BatchDelete(ctx, req, requestCallOptions(callOpts, "delete-"+strconv.Itoa(start))...); err != nil {
+			for _, i := range deletes[start:end] {
+				plan.Actions[i].Err = err
+			}
+		}
+	}
+
+	var errs []error
+	for _, action := range plan.Actions {
+		if action.Err != nil {
+			errs = append(errs, fmt.Errorf("%s %s: %w", action.Type, action.Path, action.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncPath 返回远端文档对应的相对路径
+func syncPath(doc *Document) string {
+	if m, ok := doc.Metadata.Data.(map[string]interface{}); ok {
+		if p, ok := m[SyncPathMetadataKey].(string); ok && p != "" {
+			return p
+		}
+	}
+	return path.Clean(strings.ReplaceAll(doc.Filename, "\\", "/"))
+}
+
+// fileChanged 判断本地文件与远端文档内容是否不同
+func fileChanged(fsys fs.FS, p string, size int64, doc *Document, newHash func() hash.Hash) (bool, error) {
+	if doc.FileHash == "" {
+		return size != doc.FileSize, nil
+	}
+
+	if newHash == nil {
+		switch len(doc.FileHash) {
+		case hex.EncodedLen(md5.Size):
+			newHash = md5.New
+		case hex.EncodedLen(sha1.Size):
+			newHash = sha1.New
+		default:
+			newHash = sha256.New
+		}
+	}
+
+	file, err := fsys.Open(p)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	h := newHash()
+	if _, err := io.Copy(h, file); err != nil {
+		return false, err
+	}
+	return !strings.EqualFold(hex.EncodeToString(h.Sum(nil)), doc.FileHash), nil
+}
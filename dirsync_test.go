@@ -0,0 +1,135 @@
+package sdk
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+// newDocumentsServer 返回只支持列出文档的测试服务
+func newDocumentsServer(t *testing.T, docs []Document) *Client {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/datasets/ds-1/documents" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"success": true,
+			"data": ListDocumentsResponse{
+				Documents: docs,
+				Total:     int64(len(docs)),
+			},
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return newTestClient(t, srv.URL)
+}
+
+func TestSyncFSPlan(t *testing.T) {
+	alpha := md5.Sum([]byte("alpha"))
+	betaOld := sha256.Sum256([]byte("beta-old"))
+	remote := []Document{
+		// 通过 metadata 中的 sync_path 匹配，MD5 相同
+		{ID: "doc-a", Filename: "renamed.md", FileHash: hex.EncodeToString(alpha[:]), FileSize: 5,
+			Metadata: JSON{Data: map[string]interface{}{SyncPathMetadataKey: "a.md"}}},
+		// 与 doc-a 路径重复
+		{ID: "doc-a2", Filename: "a.md"},
+		// 通过 Filename 匹配，SHA-256 不同
+		{ID: "doc-b", Filename: "b.md", FileHash: hex.EncodeToString(betaOld[:]), FileSize: 8},
+		// 没有 FileHash 时按大小判断
+		{ID: "doc-c", Filename: "c.md", FileSize: 3},
+		{ID: "doc-d", Filename: "d.md", FileSize: 2},
+		// 本地已删除
+		{ID: "doc-gone", Filename: "gone.md", FileSize: 5},
+	}
+	local := fstest.MapFS{
+		"a.md":         {Data: []byte("alpha")},
+		"b.md":         {Data: []byte("beta-new")},
+		"c.md":         {Data: []byte("ccc")},
+		"d.md":         {Data: []byte("dddd")},
+		"new.md":       {Data: []byte("new")},
+		"sub/e.md":     {Data: []byte("e")},
+		".env":         {Data: []byte("SECRET=1")},
+		".hidden/x.md": {Data: []byte("x")},
+	}
+
+	matched := []SyncAction{
+		{Type: SyncActionUnchanged, Path: "a.md", DocumentID: "doc-a", Size: 5},
+		{Type: SyncActionReplace, Path: "b.md", DocumentID: "doc-b", Size: 8},
+		{Type: SyncActionUnchanged, Path: "c.md", DocumentID: "doc-c", Size: 3},
+		{Type: SyncActionReplace, Path: "d.md", DocumentID: "doc-d", Size: 4},
+		{Type: SyncActionCreate, Path: "new.md", Size: 3},
+		{Type: SyncActionCreate, Path: "sub/e.md", Size: 1},
+	}
+
+	tests := []struct {
+		name string
+		opts SyncOptions
+		want []SyncAction
+	}{
+		{
+			name: "remote documents are kept by default",
+			want: matched,
+		},
+		{
+			name: "delete removes unmatched and duplicate documents",
+			opts: SyncOptions{Delete: true},
+			want: append(append([]SyncAction(nil), matched...),
+				SyncAction{Type: SyncActionDelete, Path: "a.md", DocumentID: "doc-a2"},
+				SyncAction{Type: SyncActionDelete, Path: "gone.md", DocumentID: "doc-gone", Size: 5},
+			),
+		},
+		{
+			name: "filter replaces the default hidden file rule",
+			opts: SyncOptions{Filter: func(relPath string, d fs.DirEntry) bool {
+				return d.IsDir() || relPath == ".env" || relPath == "a.md"
+			}},
+			want: []SyncAction{
+				{Type: SyncActionCreate, Path: ".env", Size: 8},
+				{Type: SyncActionUnchanged, Path: "a.md", DocumentID: "doc-a", Size: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newDocumentsServer(t, remote)
+			opts := tt.opts
+			opts.DryRun = true
+
+			plan, err := client.Documents.SyncFS(context.Background(), "ds-1", local, &opts)
+			if err != nil {
+				t.Fatalf("SyncFS: %v", err)
+			}
+			if !plan.DryRun || plan.DatasetID != "ds-1" {
+				t.Errorf("plan = {DatasetID: %q, DryRun: %v}, want ds-1 dry run", plan.DatasetID, plan.DryRun)
+			}
+			if !reflect.DeepEqual(plan.Actions, tt.want) {
+				t.Errorf("actions =\n%+v\nwant\n%+v", plan.Actions, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncPlanString(t *testing.T) {
+	plan := &SyncPlan{Actions: []SyncAction{
+		{Type: SyncActionCreate, Path: "new.md"},
+		{Type: SyncActionReplace, Path: "b.md", DocumentID: "doc-b"},
+		{Type: SyncActionUnchanged, Path: "a.md", DocumentID: "doc-a"},
+		{Type: SyncActionDelete, Path: "gone.md", DocumentID: "doc-gone"},
+	}}
+	want := "+ new.md\n~ b.md (doc-b)\n- gone.md (doc-gone)\n1 to create, 1 to replace, 1 to delete, 1 unchanged\n"
+	if got := plan.String(); got != want {
+		t.Errorf("String() =\n%s\nwant\n%s", got, want)
+	}
+}
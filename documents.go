@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
-	"net/http"
 )
 
 // DocumentsService 文档管理服务
@@ -32,6 +31,20 @@ type UploadDocumentRequest struct {
 	KeywordsOnlyMode bool
 }
 
+// Validate 校验请求参数
+func (r *UploadDocumentRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("dataset_id", r.DatasetID)
+	v.required("filename", r.Filename)
+	if r.File == nil {
+		v.addf("file", "is required")
+	}
+	return v.err()
+}
+
 // UploadDocumentResponse 上传文档响应
 type UploadDocumentResponse struct {
 	DocumentID string `json:"document_id"`
@@ -47,7 +60,18 @@ type ListDocumentsRequest struct {
 	DatasetID   string
 	DocumentIDs []string // 可选：按文档 ID 列表过滤
 	Page        int      // 页码，默认 1
-	PageSize    int      // 每页数量，默认 20，设为 0 则不分页
+	PageSize    int      // 每页数量，默认 20，设为 PageSizeAll 则不分页
+}
+
+// Validate 校验请求参数
+func (r *ListDocumentsRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("dataset_id", r.DatasetID)
+	v.page(r.Page, r.PageSize)
+	return v.err()
 }
 
 // ListDocumentsResponse 列表响应
@@ -64,30 +88,64 @@ type BatchDeleteDocumentsRequest struct {
 	DocumentIDs []string `json:"document_ids"`
 }
 
+// Validate 校验请求参数
+func (r *BatchDeleteDocumentsRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("dataset_id", r.DatasetID)
+	if len(r.DocumentIDs) == 0 {
+		v.addf("document_ids", "must not be empty")
+	}
+	for i, id := range r.DocumentIDs {
+		v.required(fmt.Sprintf("document_ids[%d]", i), id)
+	}
+	return v.err()
+}
+
 // Upload 上传文档
-func (s *DocumentsService) Upload(ctx context.Context, req *UploadDocumentRequest) (*UploadDocumentResponse, error) {
-	// 创建 multipart form
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
+func (s *DocumentsService) Upload(ctx context.Context, req *UploadDocumentRequest, opts ...CallOption) (*UploadDocumentResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
 
-	// 添加文件
-	part, err := writer.CreateFormFile("file", req.Filename)
+	var result UploadDocumentResponse
+	op := &Operation{
+		Service:    "Documents",
+		Method:     "Upload",
+		HTTPMethod: "POST",
+		Path:       fmt.Sprintf("/api/v1/datasets/%s/documents", req.DatasetID),
+		DatasetID:  req.DatasetID,
+		Request:    req,
+		Result:     &result,
+	}
+	ctx, cancel := s.client.prepare(ctx, op, opts)
+	defer cancel()
+	err := s.client.invoke(ctx, op, func(ctx context.Context, op *Operation) error {
+		return s.upload(ctx, op, req)
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create form file: %w", err)
+		return nil, err
 	}
+	return &result, nil
+}
 
-	if _, err := io.Copy(part, req.File); err != nil {
-		return nil, fmt.Errorf("failed to copy file: %w", err)
-	}
+// upload 构造 multipart 请求体并发送，在中间件之后执行，文件内容在发送时才读取
+func (s *DocumentsService) upload(ctx context.Context, op *Operation, req *UploadDocumentRequest) error {
+	// 创建 multipart form
+	// 先写入普通字段和文件头，文件内容在发送时以流的方式读取，不在内存中缓存
+	prefix := &bytes.Buffer{}
+	writer := multipart.NewWriter(prefix)
 
 	// 添加 tags
 	if len(req.Tags) > 0 {
 		tagsJSON, err := json.Marshal(req.Tags)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal tags: %w", err)
+			return fmt.Errorf("failed to marshal tags: %w", err)
 		}
 		if err := writer.WriteField("tags", string(tagsJSON)); err != nil {
-			return nil, fmt.Errorf("failed to write tags field: %w", err)
+			return fmt.Errorf("failed to write tags field: %w", err)
 		}
 	}
 
@@ -95,92 +153,65 @@ func (s *DocumentsService) Upload(ctx context.Context, req *UploadDocumentReques
 	if req.Metadata != nil {
 		metadataJSON, err := json.Marshal(req.Metadata)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal metadata: %w", err)
+			return fmt.Errorf("failed to marshal metadata: %w", err)
 		}
 		if err := writer.WriteField("metadata", string(metadataJSON)); err != nil {
-			return nil, fmt.Errorf("failed to write metadata field: %w", err)
+			return fmt.Errorf("failed to write metadata field: %w", err)
 		}
 	}
 
 	// 添加可选的 document_id（用于更新）
 	if req.DocumentID != "" {
 		if err := writer.WriteField("document_id", req.DocumentID); err != nil {
-			return nil, fmt.Errorf("failed to write document_id field: %w", err)
+			return fmt.Errorf("failed to write document_id field: %w", err)
 		}
 	}
 
 	if req.Title != "" {
 		if err := writer.WriteField("title", req.Title); err != nil {
-			return nil, fmt.Errorf("failed to write title field: %w", err)
+			return fmt.Errorf("failed to write title field: %w", err)
 		}
 	}
 
 	// 添加 extract_keywords
 	if req.ExtractKeywords {
 		if err := writer.WriteField("extract_keywords", "true"); err != nil {
-			return nil, fmt.Errorf("failed to write extract_keywords field: %w", err)
+			return fmt.Errorf("failed to write extract_keywords field: %w", err)
 		}
 	}
 
 	if req.KeywordsOnlyMode {
 		if err := writer.WriteField("keywords_only_mode", "true"); err != nil {
-			return nil, fmt.Errorf("failed to write keywords_only_mode field: %w", err)
+			return fmt.Errorf("failed to write keywords_only_mode field: %w", err)
 		}
 	}
 
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close writer: %w", err)
+	// 添加文件，文件放在最后以便流式发送
+	if _, err := writer.CreateFormFile("file", req.Filename); err != nil {
+		return fmt.Errorf("failed to create form file: %w", err)
 	}
 
-	// 发送请求
-	path := fmt.Sprintf("/api/v1/datasets/%s/documents", req.DatasetID)
-	fullURL := s.client.baseURL + path
-
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", fullURL, body)
+	body, err := newMultipartBody(prefix.Bytes(), req.File, writer.Boundary())
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return err
 	}
-	httpReq.Header.Set("Content-Type", writer.FormDataContentType())
 
-	resp, err := s.client.httpClient.Do(httpReq)
+	resp, err := s.client.sendRequest(ctx, op, &requestBody{
+		contentType: writer.FormDataContentType(),
+		open:        body.open,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return err
 	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	// 检查状态码
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var apiErr APIError
-		if err := json.Unmarshal(respBody, &apiErr); err != nil {
-			return nil, &APIError{
-				StatusCode: resp.StatusCode,
-				Message:    string(respBody),
-			}
-		}
-		apiErr.StatusCode = resp.StatusCode
-		return nil, &apiErr
-	}
-
-	// 解析响应
-	var apiResp struct {
-		Success bool                   `json:"success"`
-		Message string                 `json:"message"`
-		Data    UploadDocumentResponse `json:"data"`
-	}
-	if err := json.Unmarshal(respBody, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
-
-	return &apiResp.Data, nil
+	return decodeResponse(resp, op.Result)
 }
 
 // List 列出文档
-func (s *DocumentsService) List(ctx context.Context, req *ListDocumentsRequest) (*ListDocumentsResponse, error) {
+func (s *DocumentsService) List(ctx context.Context, req *ListDocumentsRequest, opts ...CallOption) (*ListDocumentsResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	params := make(map[string]string)
 
 	// 添加 document_ids 参数（逗号分隔）
@@ -195,29 +226,48 @@ func (s *DocumentsService) List(ctx context.Context, req *ListDocumentsRequest)
 	}
 
 	// 添加分页参数
-	if req.Page > 0 {
-		params["page"] = fmt.Sprintf("%d", req.Page)
-	}
-	if req.PageSize >= 0 {
-		params["page_size"] = fmt.Sprintf("%d", req.PageSize)
-	}
+	setPageParams(params, req.Page, req.PageSize)
 
 	path := fmt.Sprintf("/api/v1/datasets/%s/documents", req.DatasetID)
 	path = s.client.buildURL(path, params)
 
 	var result ListDocumentsResponse
-	err := s.client.do(ctx, "GET", path, nil, &result)
+	op := &Operation{Service: "Documents", Method: "List", HTTPMethod: "GET", Path: path, DatasetID: req.DatasetID, Request: req}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
+// ListPager 返回逐页列出文档的迭代器，req 为 nil 时按空请求处理，其中的 Page 和 PageSize 会被忽略，callOpts 作用于每一页的请求
+func (s *DocumentsService) ListPager(req *ListDocumentsRequest, opts *PagerOptions, callOpts ...CallOption) *Pager[Document] {
+	return NewPager(func(ctx context.Context, page, pageSize int) ([]Document, int64, error) {
+		var pageReq ListDocumentsRequest
+		if req != nil {
+			pageReq = *req
+		}
+		pageReq.Page = page
+		pageReq.PageSize = pageSize
+
+		resp, err := s.List(ctx, &pageReq, callOpts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Documents, resp.Total, nil
+	}, opts)
+}
+
 // Get 获取文档详情
-func (s *DocumentsService) Get(ctx context.Context, datasetID, documentID string) (*Document, error) {
+func (s *DocumentsService) Get(ctx context.Context, datasetID, documentID string, opts ...CallOption) (*Document, error) {
+	if err := s.client.validateIDs("dataset_id", datasetID, "document_id", documentID); err != nil {
+		return nil, err
+	}
+
 	var result Document
 	path := fmt.Sprintf("/api/v1/datasets/%s/documents/%s", datasetID, documentID)
-	err := s.client.do(ctx, "GET", path, nil, &result)
+	op := &Operation{Service: "Documents", Method: "Get", HTTPMethod: "GET", Path: path, DatasetID: datasetID}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -225,13 +275,22 @@ func (s *DocumentsService) Get(ctx context.Context, datasetID, documentID string
 }
 
 // Delete 删除文档
-func (s *DocumentsService) Delete(ctx context.Context, datasetID, documentID string) error {
+func (s *DocumentsService) Delete(ctx context.Context, datasetID, documentID string, opts ...CallOption) error {
+	if err := s.client.validateIDs("dataset_id", datasetID, "document_id", documentID); err != nil {
+		return err
+	}
+
 	path := fmt.Sprintf("/api/v1/datasets/%s/documents/%s", datasetID, documentID)
-	return s.client.do(ctx, "DELETE", path, nil, nil)
+	op := &Operation{Service: "Documents", Method: "Delete", HTTPMethod: "DELETE", Path: path, DatasetID: datasetID}
+	return s.client.do(ctx, op, nil, nil, opts...)
 }
 
 // BatchDelete 批量删除文档
-func (s *DocumentsService) BatchDelete(ctx context.Context, req *BatchDeleteDocumentsRequest) error {
+func (s *DocumentsService) BatchDelete(ctx context.Context, req *BatchDeleteDocumentsRequest, opts ...CallOption) error {
+	if err := s.client.validate(req); err != nil {
+		return err
+	}
+
 	if len(req.DocumentIDs) == 0 {
 		return nil
 	}
@@ -242,7 +301,8 @@ func (s *DocumentsService) BatchDelete(ctx context.Context, req *BatchDeleteDocu
 		"document_ids": req.DocumentIDs,
 	}
 
-	return s.client.do(ctx, "POST", path, body, nil)
+	op := &Operation{Service: "Documents", Method: "BatchDelete", HTTPMethod: "POST", Path: path, DatasetID: req.DatasetID, Request: req}
+	return s.client.do(ctx, op, body, nil, opts...)
 }
 
 // UpdateDocumentRequest 更新文档请求
@@ -253,6 +313,17 @@ type UpdateDocumentRequest struct {
 	Tags       []string               `json:"tags,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *UpdateDocumentRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("dataset_id", r.DatasetID)
+	v.required("document_id", r.DocumentID)
+	return v.err()
+}
+
 // ReindexResponse 重新索引单个文档响应
 type ReindexResponse struct {
 	Message    string `json:"message"`
@@ -260,10 +331,15 @@ type ReindexResponse struct {
 }
 
 // Reindex 重新索引单个文档
-func (s *DocumentsService) Reindex(ctx context.Context, datasetID, documentID string) (*ReindexResponse, error) {
+func (s *DocumentsService) Reindex(ctx context.Context, datasetID, documentID string, opts ...CallOption) (*ReindexResponse, error) {
+	if err := s.client.validateIDs("dataset_id", datasetID, "document_id", documentID); err != nil {
+		return nil, err
+	}
+
 	var result ReindexResponse
 	path := fmt.Sprintf("/api/v1/datasets/%s/documents/%s/reindex", datasetID, documentID)
-	err := s.client.do(ctx, "POST", path, nil, &result)
+	op := &Operation{Service: "Documents", Method: "Reindex", HTTPMethod: "POST", Path: path, DatasetID: datasetID}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -271,7 +347,11 @@ func (s *DocumentsService) Reindex(ctx context.Context, datasetID, documentID st
 }
 
 // Update 更新文档的 metadata 和 tags
-func (s *DocumentsService) Update(ctx context.Context, req *UpdateDocumentRequest) (*Document, error) {
+func (s *DocumentsService) Update(ctx context.Context, req *UpdateDocumentRequest, opts ...CallOption) (*Document, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result Document
 	path := fmt.Sprintf("/api/v1/datasets/%s/documents/%s", req.DatasetID, req.DocumentID)
 
@@ -283,7 +363,8 @@ func (s *DocumentsService) Update(ctx context.Context, req *UpdateDocumentReques
 		body["tags"] = req.Tags
 	}
 
-	err := s.client.do(ctx, "PATCH", path, body, &result)
+	op := &Operation{Service: "Documents", Method: "Update", HTTPMethod: "PATCH", Path: path, DatasetID: req.DatasetID, Request: req}
+	err := s.client.do(ctx, op, body, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,256 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EndpointStrategy 配置了多个服务地址时选择地址的策略
+type EndpointStrategy int
+
+// 地址选择策略
+const (
+	StrategyRoundRobin    EndpointStrategy = iota // 轮流使用健康的地址
+	StrategyLeastInFlight                         // 使用进行中请求最少的健康地址
+	StrategyFailover                              // 按配置顺序使用第一个健康的地址，前面的地址不可用时才使用后面的
+)
+
+const (
+	// endpointFailureThreshold 连续失败多少次后地址被视为不健康
+	endpointFailureThreshold = 3
+
+	// endpointCooldown 不健康的地址经过多久后重新参与选择
+	endpointCooldown = 30 * time.Second
+
+	// healthCheckTimeout 主动健康检查单次请求的超时时间
+	healthCheckTimeout = 5 * time.Second
+)
+
+// endpoint 一个服务地址及其健康状态
+type endpoint struct {
+	baseURL  string
+	inFlight atomic.Int64
+
+	mu          sync.Mutex
+	failures    int
+	lastFailure time.Time
+}
+
+// begin 记录一个进行中的请求，返回的函数结束该请求，可以重复调用
+func (e *endpoint) begin() func() {
+	e.inFlight.Add(1)
+	var once sync.Once
+	return func() {
+		once.Do(func() { e.inFlight.Add(-1) })
+	}
+}
+
+// record 根据请求结果被动更新健康状态
+func (e *endpoint) record(failed bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if !failed {
+		e.failures = 0
+		return
+	}
+	e.failures++
+	e.lastFailure = time.Now()
+}
+
+// setHealthy 根据主动健康检查的结果更新健康状态，检查失败时立即视为不健康
+func (e *endpoint) setHealthy(healthy bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if healthy {
+		e.failures = 0
+		return
+	}
+	if e.failures < endpointFailureThreshold {
+		e.failures = endpointFailureThreshold
+	}
+	e.lastFailure = time.Now()
+}
+
+// healthy 连续失败次数未达到阈值，或者距离上次失败已超过冷却时间
+func (e *endpoint) healthy() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.failures < endpointFailureThreshold || time.Since(e.lastFailure) >= endpointCooldown
+}
+
+// newEndpoints 由 baseURL 和 WithEndpoints 添加的地址创建地址列表，忽略空地址和重复地址
+func newEndpoints(baseURLs []string) []*endpoint {
+	seen := make(map[string]bool, len(baseURLs))
+	endpoints := make([]*endpoint, 0, len(baseURLs))
+	for _, u := range baseURLs {
+		if u == "" || seen[u] {
+			continue
+		}
+		seen[u] = true
+		endpoints = append(endpoints, &endpoint{baseURL: u})
+	}
+	return endpoints
+}
+
+// endpointFor 选择本次尝试使用的地址及其熔断器
+//
+// 优先选择本次操作还没有尝试过的地址，跳过熔断器打开的地址；
+// 所有地址的熔断器都打开时返回 *CircuitOpenError。
+func (c *Client) endpointFor(ctx context.Context, tried map[*endpoint]bool) (*endpoint, *circuitBreaker, error) {
+	open := make(map[*endpoint]bool)
+	var openErr error
+	for {
+		ep := c.pickEndpoint(func(ep *endpoint) bool { return open[ep] || tried[ep] })
+		if ep == nil {
+			ep = c.pickEndpoint(func(ep *endpoint) bool { return open[ep] })
+		}
+		if ep == nil {
+			return nil, nil, openErr
+		}
+
+		breaker := c.breaker(ep.baseURL)
+		if breaker == nil {
+			return ep, nil, nil
+		}
+		if err := breaker.allow(ctx); err != nil {
+			open[ep] = true
+			openErr = err
+			continue
+		}
+		return ep, breaker, nil
+	}
+}
+
+// pickEndpoint 按策略从未被 skip 排除的地址中选择一个，健康的地址优先
+func (c *Client) pickEndpoint(skip func(*endpoint) bool) *endpoint {
+	var healthy, unhealthy []*endpoint
+	for _, ep := range c.endpoints {
+		if skip(ep) {
+			continue
+		}
+		if ep.healthy() {
+			healthy = append(healthy, ep)
+		} else {
+			unhealthy = append(unhealthy, ep)
+		}
+	}
+	candidates := healthy
+	if len(candidates) == 0 {
+		candidates = unhealthy
+	}
+	if len(candidates) <= 1 {
+		if len(candidates) == 0 {
+			return nil
+		}
+		return candidates[0]
+	}
+
+	switch c.strategy {
+	case StrategyFailover:
+		return candidates[0]
+	case StrategyLeastInFlight:
+		// 从轮转位置开始比较，进行中请求数相同时不会总是选中第一个地址
+		start := int(c.nextEndpoint.Add(1) % uint64(len(candidates)))
+		best := candidates[start]
+		for i := 1; i < len(candidates); i++ {
+			ep := candidates[(start+i)%len(candidates)]
+			if ep.inFlight.Load() < best.inFlight.Load() {
+				best = ep
+			}
+		}
+		return best
+	default:
+		return candidates[int((c.nextEndpoint.Add(1)-1)%uint64(len(candidates)))]
+	}
+}
+
+// hasUntried 本次操作是否还有没有尝试过的地址
+func (c *Client) hasUntried(tried map[*endpoint]bool) bool {
+	return len(tried) < len(c.endpoints)
+}
+
+// requestNotSent 连接没有建立，请求一定没有到达服务端，非幂等请求也可以安全地切换地址
+func requestNotSent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// runHealthChecks 定期对所有地址执行主动健康检查，直到 Close 被调用
+func (c *Client) runHealthChecks(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			var wg sync.WaitGroup
+			for _, ep := range c.endpoints {
+				wg.Add(1)
+				go func(ep *endpoint) {
+					defer wg.Done()
+					ep.setHealthy(c.checkEndpoint(ep) == nil)
+				}(ep)
+			}
+			wg.Wait()
+		}
+	}
+}
+
+// checkEndpoint 主动健康检查一个地址，超时时间为 healthCheckTimeout
+func (c *Client) checkEndpoint(ep *endpoint) error {
+	ctx, cancel := context.WithTimeout(context.Background(), healthCheckTimeout)
+	defer cancel()
+	return c.probeHealth(ctx, ep.baseURL)
+}
+
+// probeHealth 请求 baseURL 的 /health，2xx 表示健康
+//
+// 直接使用 HTTP 客户端发送，不经过中间件、重试、限流和日志，避免定期检查和熔断探测产生大量记录或排队。
+// 与普通请求一样携带 credentials 提供的令牌，服务端要求认证时探测才能成功。
+func (c *Client) probeHealth(ctx context.Context, baseURL string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if c.credentials != nil {
+		token, err := c.credentials.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get credentials: %w", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Close 停止 WithHealthCheck 启动的后台健康检查，未配置时无需调用；可以重复调用
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		if c.closed != nil {
+			close(c.closed)
+		}
+	})
+	return nil
+}
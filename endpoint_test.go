@@ -0,0 +1,294 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPickEndpoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy EndpointStrategy
+		setup    func(eps []*endpoint)
+		want     []string
+	}{
+		{
+			name:     "round robin cycles through endpoints",
+			strategy: StrategyRoundRobin,
+			want:     []string{"a", "b", "c", "a", "b"},
+		},
+		{
+			name:     "round robin skips unhealthy endpoints",
+			strategy: StrategyRoundRobin,
+			setup:    func(eps []*endpoint) { eps[1].setHealthy(false) },
+			want:     []string{"a", "c", "a", "c"},
+		},
+		{
+			name:     "failover uses the first healthy endpoint",
+			strategy: StrategyFailover,
+			want:     []string{"a", "a", "a"},
+		},
+		{
+			name:     "failover moves on after passive failures",
+			strategy: StrategyFailover,
+			setup: func(eps []*endpoint) {
+				for i := 0; i < endpointFailureThreshold; i++ {
+					eps[0].record(true)
+				}
+			},
+			want: []string{"b", "b"},
+		},
+		{
+			name:     "failover returns after the cooldown",
+			strategy: StrategyFailover,
+			setup: func(eps []*endpoint) {
+				eps[0].setHealthy(false)
+				eps[0].lastFailure = time.Now().Add(-endpointCooldown)
+			},
+			want: []string{"a"},
+		},
+		{
+			name:     "least in flight",
+			strategy: StrategyLeastInFlight,
+			setup: func(eps []*endpoint) {
+				eps[0].inFlight.Store(2)
+				eps[1].inFlight.Store(1)
+				eps[2].inFlight.Store(3)
+			},
+			want: []string{"b", "b"},
+		},
+		{
+			name:     "all unhealthy still picks an endpoint",
+			strategy: StrategyFailover,
+			setup: func(eps []*endpoint) {
+				for _, ep := range eps {
+					ep.setHealthy(false)
+				}
+			},
+			want: []string{"a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{endpoints: newEndpoints([]string{"a", "b", "", "c", "a"}), strategy: tt.strategy}
+			if tt.setup != nil {
+				tt.setup(c.endpoints)
+			}
+			var got []string
+			for range tt.want {
+				got = append(got, c.pickEndpoint(func(*endpoint) bool { return false }).baseURL)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("picked %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpointHealth(t *testing.T) {
+	ep := &endpoint{baseURL: "a"}
+	for i := 0; i < endpointFailureThreshold-1; i++ {
+		ep.record(true)
+	}
+	if !ep.healthy() {
+		t.Fatalf("unhealthy after %d failures, want healthy below the threshold", endpointFailureThreshold-1)
+	}
+	ep.record(true)
+	if ep.healthy() {
+		t.Fatal("healthy after reaching the failure threshold")
+	}
+	ep.record(false)
+	if !ep.healthy() {
+		t.Error("unhealthy after a success, want the failure count reset")
+	}
+}
+
+// closedServerURL 返回一个已关闭服务的地址，连接会被拒绝
+func closedServerURL() string {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+	return srv.URL
+}
+
+func TestSendFailover(t *testing.T) {
+	tests := []struct {
+		name          string
+		primary       []int
+		primaryClosed bool
+		call          func(ctx context.Context, c *Client) error
+		wantPrimary   int
+		wantSecondary int
+		wantStatus    int
+	}{
+		{
+			name:          "GET fails over on 5xx without a retry",
+			primary:       []int{503},
+			call:          getDataset,
+			wantPrimary:   1,
+			wantSecondary: 1,
+		},
+		{
+			name:          "GET fails over on connection failure",
+			primaryClosed: true,
+			call:          getDataset,
+			wantSecondary: 1,
+		},
+		{
+			name:        "GET does not fail over on 4xx",
+			primary:     []int{404},
+			call:        getDataset,
+			wantPrimary: 1,
+			wantStatus:  404,
+		},
+		{
+			name:        "POST does not fail over on 5xx",
+			primary:     []int{503},
+			call:        createDataset(),
+			wantPrimary: 1,
+			wantStatus:  503,
+		},
+		{
+			name:          "POST fails over when the connection was not established",
+			primaryClosed: true,
+			call:          createDataset(),
+			wantSecondary: 1,
+		},
+		{
+			name:          "POST with idempotency key fails over on 5xx",
+			primary:       []int{503},
+			call:          createDataset(WithIdempotencyKey("key")),
+			wantPrimary:   1,
+			wantSecondary: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := newScriptedServer(t, tt.primary...)
+			primaryURL := primary.URL
+			if tt.primaryClosed {
+				primaryURL = closedServerURL()
+			}
+			secondary := newScriptedServer(t)
+			client := newTestClient(t, primaryURL,
+				WithEndpoints(secondary.URL),
+				WithEndpointStrategy(StrategyFailover),
+				WithRetryPolicy(fastRetryPolicy(0)),
+			)
+
+			err := tt.call(context.Background(), client)
+			if got := primary.count(); got != tt.wantPrimary {
+				t.Errorf("primary requests = %d, want %d", got, tt.wantPrimary)
+			}
+			if got := secondary.count(); got != tt.wantSecondary {
+				t.Errorf("secondary requests = %d, want %d", got, tt.wantSecondary)
+			}
+			if tt.wantStatus == 0 {
+				if err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				return
+			}
+			var apiErr *APIError
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.wantStatus {
+				t.Fatalf("err = %v, want APIError with status %d", err, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestSendFailoverSkipsOpenCircuit(t *testing.T) {
+	primary := newScriptedServer(t, 503)
+	secondary := newScriptedServer(t)
+	client := newTestClient(t, primary.URL,
+		WithEndpoints(secondary.URL),
+		WithEndpointStrategy(StrategyFailover),
+		WithRetryPolicy(fastRetryPolicy(0)),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour}),
+	)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := getDataset(ctx, client); err != nil {
+			t.Fatalf("request %d: %v", i+1, err)
+		}
+	}
+	if got := primary.count(); got != 1 {
+		t.Errorf("primary requests = %d, want 1 before its breaker opened", got)
+	}
+	if got := secondary.count(); got != 3 {
+		t.Errorf("secondary requests = %d, want 3", got)
+	}
+}
+
+func TestHealthCheckMarksEndpointUnhealthy(t *testing.T) {
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"data":{"id":"ds-1"}}`))
+	}))
+	t.Cleanup(primary.Close)
+	secondary := newScriptedServer(t)
+	client := newTestClient(t, primary.URL,
+		WithEndpoints(secondary.URL),
+		WithEndpointStrategy(StrategyFailover),
+		WithHealthCheck(10*time.Millisecond),
+	)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for client.endpoints[0].healthy() {
+		if time.Now().After(deadline) {
+			t.Fatal("primary still healthy after failed health checks")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if err := getDataset(context.Background(), client); err != nil {
+		t.Fatal(err)
+	}
+	var paths []string
+	for _, r := range secondary.received() {
+		if r.URL.Path != "/health" {
+			paths = append(paths, r.URL.Path)
+		}
+	}
+	if len(paths) != 1 {
+		t.Errorf("secondary API requests = %q, want 1 while the primary is unhealthy", paths)
+	}
+}
+
+func TestHealthCheckUsesCredentials(t *testing.T) {
+	servers := []*scriptedServer{newScriptedServer(t), newScriptedServer(t)}
+	for _, srv := range servers {
+		srv.apiKey = "k"
+	}
+	client := newTestClient(t, servers[0].URL,
+		WithEndpoints(servers[1].URL),
+		WithAPIKey("k"),
+		WithHealthCheck(10*time.Millisecond),
+	)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for servers[0].count() < 2 || servers[1].count() < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("health checks did not run")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	for i, srv := range servers {
+		for _, r := range srv.received() {
+			if got := r.Header.Get("Authorization"); got != "Bearer k" {
+				t.Errorf("endpoint %d: %s sent with Authorization %q, want Bearer k", i, r.URL.Path, got)
+			}
+		}
+		if !client.endpoints[i].healthy() {
+			t.Errorf("endpoint %d unhealthy, want authenticated health checks to pass", i)
+		}
+	}
+}
@@ -1,16 +1,112 @@
 package sdk
 
-import "fmt"
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// 可以配合 errors.Is 使用的错误类型，*APIError 按状态码匹配：
+//
+//	if errors.Is(err, sdk.ErrNotFound) { ... }
+var (
+	ErrValidation   = errors.New("sdk: validation failed")   // 400、422
+	ErrUnauthorized = errors.New("sdk: unauthorized")        // 401
+	ErrForbidden    = errors.New("sdk: forbidden")           // 403
+	ErrNotFound     = errors.New("sdk: not found")           // 404
+	ErrConflict     = errors.New("sdk: conflict")            // 409
+	ErrRateLimited  = errors.New("sdk: rate limited")        // 429
+	ErrServer       = errors.New("sdk: server error")        // 5xx
+	ErrUnavailable  = errors.New("sdk: service unavailable") // 502、503、504
+)
+
+// ErrCircuitOpen 熔断器打开，请求没有发送，由 *CircuitOpenError 匹配
+var ErrCircuitOpen = errors.New("sdk: circuit breaker open")
+
+// FieldError 字段级别的校验错误
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Error 实现 error 接口
+func (e *FieldError) Error() string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
 
 // APIError API 错误
 type APIError struct {
 	StatusCode int
 	Message    string
+
+	// 服务端返回的错误码，没有时为空
+	Code string
+
+	// 请求 ID，来自响应体或 X-Request-Id 响应头，服务端没有返回时为客户端发送的请求 ID，
+	// 与 ResponseMeta.RequestID 相同，用于与服务端日志关联
+	RequestID string
+
+	// 字段级别的校验错误
+	Details []FieldError
+
+	// 原始响应体
+	Body []byte
 }
 
 // Error 实现 error 接口
 func (e *APIError) Error() string {
-	return fmt.Sprintf("API error (status %d): %s", e.StatusCode, e.Message)
+	var b strings.Builder
+	fmt.Fprintf(&b, "API error (status %d", e.StatusCode)
+	if e.Code != "" {
+		fmt.Fprintf(&b, ", code %s", e.Code)
+	}
+	fmt.Fprintf(&b, "): %s", e.Message)
+	if len(e.Details) > 0 {
+		details := make([]string, len(e.Details))
+		for i := range e.Details {
+			details[i] = e.Details[i].Error()
+		}
+		fmt.Fprintf(&b, " (%s)", strings.Join(details, "; "))
+	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&b, " [request_id=%s]", e.RequestID)
+	}
+	return b.String()
+}
+
+// Is 支持 errors.Is(err, sdk.ErrNotFound) 等按状态码判断错误类型
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.StatusCode == http.StatusConflict
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.IsServerError()
+	case ErrUnavailable:
+		switch e.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
 }
 
 // IsNotFound 是否为 404 错误
@@ -27,3 +123,145 @@ func (e *APIError) IsBadRequest() bool {
 func (e *APIError) IsServerError() bool {
 	return e.StatusCode >= 500 && e.StatusCode < 600
 }
+
+// IsRetryable 稍后重试是否可能成功：408、429、500、502、503、504
+//
+// 这只是对错误本身的分类，非幂等请求能否安全重试还取决于服务端是否已经处理了请求。
+func (e *APIError) IsRetryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+		http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// TransportError 请求没有得到 HTTP 响应，例如连接失败、TLS 错误或超时
+type TransportError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+// Error 实现 error 接口
+func (e *TransportError) Error() string {
+	return "failed to execute request: " + e.Err.Error()
+}
+
+// Unwrap 返回底层错误
+func (e *TransportError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout 是否为超时错误
+func (e *TransportError) Timeout() bool {
+	var netErr net.Error
+	return errors.Is(e.Err, context.DeadlineExceeded) || (errors.As(e.Err, &netErr) && netErr.Timeout())
+}
+
+// IsRetryable 除调用方取消外，连接失败和超时都属于临时性失败
+func (e *TransportError) IsRetryable() bool {
+	return !errors.Is(e.Err, context.Canceled)
+}
+
+// IsRetryable 判断错误是否为临时性失败，支持 *APIError 和 *TransportError，其他错误返回 false
+func IsRetryable(err error) bool {
+	var retryable interface{ IsRetryable() bool }
+	return errors.As(err, &retryable) && retryable.IsRetryable()
+}
+
+// errorBody 服务端错误响应，兼容 {"message","code","details"} 和 {"error":{...}} 两种结构
+type errorBody struct {
+	Message   string          `json:"message"`
+	Code      json.RawMessage `json:"code"`
+	Error     json.RawMessage `json:"error"`
+	Details   json.RawMessage `json:"details"`
+	RequestID string          `json:"request_id"`
+}
+
+// newAPIError 根据响应状态码、响应头和响应体构造 *APIError
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		RequestID:  resp.Header.Get(headerRequestID),
+		Body:       body,
+	}
+	if apiErr.RequestID == "" && resp.Request != nil {
+		apiErr.RequestID = resp.Request.Header.Get(headerRequestID)
+	}
+
+	var eb errorBody
+	if err := json.Unmarshal(body, &eb); err != nil {
+		apiErr.Message = strings.TrimSpace(string(body))
+		if apiErr.Message == "" {
+			apiErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return apiErr
+	}
+
+	// error 字段可能是字符串，也可能是包含 code、message、details 的对象
+	var nested errorBody
+	if len(eb.Error) > 0 && json.Unmarshal(eb.Error, &nested) == nil {
+		eb.Message = firstNonEmpty(eb.Message, nested.Message)
+		if len(eb.Code) == 0 {
+			eb.Code = nested.Code
+		}
+		if len(eb.Details) == 0 {
+			eb.Details = nested.Details
+		}
+		eb.RequestID = firstNonEmpty(eb.RequestID, nested.RequestID)
+	} else {
+		var s string
+		if json.Unmarshal(eb.Error, &s) == nil {
+			eb.Message = firstNonEmpty(eb.Message, s)
+		}
+	}
+
+	apiErr.Message = firstNonEmpty(eb.Message, http.StatusText(resp.StatusCode))
+	apiErr.Code = rawString(eb.Code)
+	apiErr.RequestID = firstNonEmpty(eb.RequestID, apiErr.RequestID)
+	apiErr.Details = parseDetails(eb.Details)
+	return apiErr
+}
+
+// parseDetails 解析字段错误，支持 [{"field","message"}] 和 {"field": "message"} 两种结构
+func parseDetails(raw json.RawMessage) []FieldError {
+	if len(raw) == 0 {
+		return nil
+	}
+	var list []FieldError
+	if json.Unmarshal(raw, &list) == nil {
+		return list
+	}
+	var m map[string]string
+	if json.Unmarshal(raw, &m) == nil && len(m) > 0 {
+		for field, msg := range m {
+			list = append(list, FieldError{Field: field, Message: msg})
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Field < list[j].Field })
+		return list
+	}
+	return nil
+}
+
+// rawString 将字符串或数字形式的 JSON 值转换为字符串
+func rawString(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,208 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    string // 响应头中的 X-Request-Id
+		reqHeader string // 请求头中的 X-Request-Id
+		body      string
+		want      APIError
+	}{
+		{
+			name:   "envelope message",
+			status: 404,
+			body:   `{"success":false,"message":"dataset not found"}`,
+			want:   APIError{StatusCode: 404, Message: "dataset not found"},
+		},
+		{
+			name:   "flat code, details list and request id",
+			status: 400,
+			header: "from-header",
+			body:   `{"message":"invalid request","code":"INVALID_ARGUMENT","details":[{"field":"query","message":"is required"}],"request_id":"from-body"}`,
+			want: APIError{
+				StatusCode: 400,
+				Message:    "invalid request",
+				Code:       "INVALID_ARGUMENT",
+				RequestID:  "from-body",
+				Details:    []FieldError{{Field: "query", Message: "is required"}},
+			},
+		},
+		{
+			name:   "nested error object with numeric code and details map",
+			status: 422,
+			body:   `{"error":{"code":1001,"message":"validation failed","details":{"top_k":"too large","query":"empty"}}}`,
+			want: APIError{
+				StatusCode: 422,
+				Message:    "validation failed",
+				Code:       "1001",
+				Details:    []FieldError{{Field: "query", Message: "empty"}, {Field: "top_k", Message: "too large"}},
+			},
+		},
+		{
+			name:   "error string",
+			status: 401,
+			body:   `{"error":"invalid api key"}`,
+			want:   APIError{StatusCode: 401, Message: "invalid api key"},
+		},
+		{
+			name:   "envelope without message falls back to status text",
+			status: 503,
+			header: "srv-1",
+			body:   `{"success":false}`,
+			want:   APIError{StatusCode: 503, Message: "Service Unavailable", RequestID: "srv-1"},
+		},
+		{
+			name:      "non-JSON body",
+			status:    502,
+			reqHeader: "client-1",
+			body:      "  <html>Bad Gateway</html>\n",
+			want:      APIError{StatusCode: 502, Message: "<html>Bad Gateway</html>", RequestID: "client-1"},
+		},
+		{
+			name:   "empty body",
+			status: 500,
+			body:   "",
+			want:   APIError{StatusCode: 500, Message: "Internal Server Error"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "http://raglite.test/api/v1/datasets", nil)
+			if tt.reqHeader != "" {
+				req.Header.Set(headerRequestID, tt.reqHeader)
+			}
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}, Request: req}
+			if tt.header != "" {
+				resp.Header.Set(headerRequestID, tt.header)
+			}
+
+			got := newAPIError(resp, []byte(tt.body))
+			if string(got.Body) != tt.body {
+				t.Errorf("Body = %q, want the raw body", got.Body)
+			}
+			got.Body = nil
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("newAPIError() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAPIErrorMessage(t *testing.T) {
+	err := &APIError{
+		StatusCode: 400,
+		Code:       "INVALID_ARGUMENT",
+		Message:    "invalid request",
+		Details:    []FieldError{{Field: "query", Message: "is required"}, {Message: "bad input"}},
+		RequestID:  "req-1",
+	}
+	want := "API error (status 400, code INVALID_ARGUMENT): invalid request (query: is required; bad input) [request_id=req-1]"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestAPIErrorSentinels(t *testing.T) {
+	sentinels := []error{ErrValidation, ErrUnauthorized, ErrForbidden, ErrNotFound, ErrConflict, ErrRateLimited, ErrServer, ErrUnavailable}
+	tests := []struct {
+		status int
+		want   []error
+	}{
+		{400, []error{ErrValidation}},
+		{401, []error{ErrUnauthorized}},
+		{403, []error{ErrForbidden}},
+		{404, []error{ErrNotFound}},
+		{408, nil},
+		{409, []error{ErrConflict}},
+		{422, []error{ErrValidation}},
+		{429, []error{ErrRateLimited}},
+		{500, []error{ErrServer}},
+		{501, []error{ErrServer}},
+		{502, []error{ErrServer, ErrUnavailable}},
+		{503, []error{ErrServer, ErrUnavailable}},
+		{504, []error{ErrServer, ErrUnavailable}},
+	}
+	for _, tt := range tests {
+		// 包装后仍然可以匹配
+		err := fmt.Errorf("get dataset: %w", &APIError{StatusCode: tt.status})
+		for _, sentinel := range sentinels {
+			want := false
+			for _, w := range tt.want {
+				want = want || w == sentinel
+			}
+			if got := errors.Is(err, sentinel); got != want {
+				t.Errorf("status %d: errors.Is(%v) = %v, want %v", tt.status, sentinel, got, want)
+			}
+		}
+		if errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("status %d matches ErrCircuitOpen", tt.status)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"400", &APIError{StatusCode: 400}, false},
+		{"401", &APIError{StatusCode: 401}, false},
+		{"404", &APIError{StatusCode: 404}, false},
+		{"408", &APIError{StatusCode: 408}, true},
+		{"429", &APIError{StatusCode: 429}, true},
+		{"500", &APIError{StatusCode: 500}, true},
+		{"501", &APIError{StatusCode: 501}, false},
+		{"502", &APIError{StatusCode: 502}, true},
+		{"503", &APIError{StatusCode: 503}, true},
+		{"504", &APIError{StatusCode: 504}, true},
+		{"wrapped 503", fmt.Errorf("list: %w", &APIError{StatusCode: 503}), true},
+		{"connection refused", &TransportError{Err: errors.New("connection refused")}, true},
+		{"timeout", &TransportError{Err: context.DeadlineExceeded}, true},
+		{"caller canceled", &TransportError{Err: context.Canceled}, false},
+		{"validation", &ValidationError{}, false},
+		{"circuit open", &CircuitOpenError{}, false},
+	}
+	for _, tt := range tests {
+		if got := IsRetryable(tt.err); got != tt.want {
+			t.Errorf("%s: IsRetryable() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTransportErrorTimeout(t *testing.T) {
+	if !(&TransportError{Err: fmt.Errorf("dial: %w", context.DeadlineExceeded)}).Timeout() {
+		t.Error("Timeout() = false for a deadline, want true")
+	}
+	if (&TransportError{Err: errors.New("connection refused")}).Timeout() {
+		t.Error("Timeout() = true for a refused connection, want false")
+	}
+}
+
+func TestDecodeResponseFailureEnvelope(t *testing.T) {
+	// 2xx 响应中 success 为 false 时同样返回 *APIError
+	srv := newScriptedServer(t)
+	srv.successful = `{"success":false,"message":"quota exceeded","code":"QUOTA"}`
+	client := newTestClient(t, srv.URL)
+
+	err := getDataset(context.Background(), client)
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != 200 || apiErr.Message != "quota exceeded" || apiErr.Code != "QUOTA" {
+		t.Fatalf("err = %v, want an *APIError with the envelope message and code", err)
+	}
+	if apiErr.RequestID == "" {
+		t.Error("RequestID is empty, want the client request ID")
+	}
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	sdk "github.com/chaitin/raglite-go-sdk"
@@ -106,8 +108,8 @@ func main() {
 		Query:               "人工智能",
 		DatasetID:           dataset.ID,
 		TopK:                10,
-		RetrievalMode:       "smart", // 使用智能检索模式
-		SimilarityThreshold: 0.7,     // 只返回相似度 > 0.7 的结果
+		RetrievalMode:       sdk.RetrievalModeSmart, // 使用智能检索模式
+		SimilarityThreshold: 0.7,                    // 只返回相似度 > 0.7 的结果
 		Tags:                []string{"技术", "AI"},
 		Metadata: map[string]interface{}{
 			"category": "research",
@@ -134,41 +136,56 @@ func main() {
 		{"doc3.md", "# 文档 3\n这是第三个文档"},
 	}
 
-	for _, doc := range documents {
-		// 这里可以使用 goroutine 并发上传
-		go func(filename, content string) {
-			_, err := client.Documents.Upload(ctx, &sdk.UploadDocumentRequest{
+	reqs := make(chan *sdk.UploadDocumentRequest)
+	go func() {
+		defer close(reqs)
+		for _, doc := range documents {
+			reqs <- &sdk.UploadDocumentRequest{
 				DatasetID: dataset.ID,
-				File:      nil, // 实际使用时应该传入文件内容
-				Filename:  filename,
-			})
-			if err != nil {
-				log.Printf("Failed to upload %s: %v", filename, err)
-			} else {
-				fmt.Printf("Uploaded: %s\n", filename)
+				File:      strings.NewReader(doc.content),
+				Filename:  doc.filename,
 			}
-		}(doc.filename, doc.content)
+		}
+	}()
+
+	// 使用固定数量的 worker 并发上传，并等待文档处理完成
+	report, err := client.Documents.UploadMany(ctx, reqs, &sdk.BulkUploadOptions{
+		Concurrency:       2,
+		RateLimit:         5,
+		WaitForProcessing: true,
+		WaitOptions:       &sdk.WaitOptions{PollInterval: time.Second},
+	})
+	if err != nil {
+		log.Printf("Bulk upload interrupted: %v", err)
 	}
-
-	// 等待上传完成
-	time.Sleep(2 * time.Second)
+	for _, result := range report.Results {
+		if result.Err != nil {
+			log.Printf("Failed to upload %s: %v", result.Request.Filename, result.Err)
+		} else {
+			fmt.Printf("Uploaded: %s (ID: %s)\n", result.Request.Filename, result.Response.DocumentID)
+		}
+	}
+	fmt.Printf("成功 %d 个，失败 %d 个，耗时 %s\n", report.Succeeded, report.Failed, report.Duration)
 	fmt.Println()
 
 	// 高级示例 6: 错误处理
 	fmt.Println("=== 错误处理示例 ===")
 	_, err = client.Datasets.Get(ctx, "non-existent-id")
 	if err != nil {
-		if apiErr, ok := err.(*sdk.APIError); ok {
-			if apiErr.IsNotFound() {
-				fmt.Println("Dataset not found (404)")
-			} else if apiErr.IsBadRequest() {
-				fmt.Println("Bad request (400)")
-			} else if apiErr.IsServerError() {
-				fmt.Println("Server error (5xx)")
-			} else {
-				fmt.Printf("API error: %v\n", apiErr)
-			}
-		} else {
+		var apiErr *sdk.APIError
+		var transportErr *sdk.TransportError
+		switch {
+		case errors.Is(err, sdk.ErrNotFound):
+			fmt.Println("Dataset not found (404)")
+		case errors.Is(err, sdk.ErrValidation):
+			fmt.Println("Bad request (400)")
+		case errors.Is(err, sdk.ErrServer):
+			fmt.Println("Server error (5xx)")
+		case errors.As(err, &apiErr):
+			fmt.Printf("API error: code=%s request_id=%s: %v\n", apiErr.Code, apiErr.RequestID, apiErr)
+		case errors.As(err, &transportErr):
+			fmt.Printf("Transport error (retryable=%v): %v\n", sdk.IsRetryable(err), err)
+		default:
 			fmt.Printf("Other error: %v\n", err)
 		}
 	}
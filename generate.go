@@ -1,6 +1,9 @@
 package sdk
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // GenerateService 生成服务
 type GenerateService struct {
@@ -12,6 +15,17 @@ type GenerateRequest struct {
 	Query     string `json:"query"`
 	Context   string `json:"context"`
 	DatasetID string `json:"dataset_id"`
+	Stream    bool   `json:"stream,omitempty"`
+}
+
+// Validate 校验请求参数
+func (r *GenerateRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("query", r.Query)
+	return v.err()
 }
 
 // GenerateResponse 生成响应
@@ -19,12 +33,130 @@ type GenerateResponse struct {
 	Answer string `json:"answer"`
 }
 
+// TokenUsage Token 用量
+type TokenUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// 流式生成事件类型
+const (
+	GenerateEventDelta = "delta" // 文本增量，见 Delta
+	GenerateEventDone  = "done"  // 生成结束，见 Answer、FinishReason 和 Usage
+	GenerateEventError = "error" // 服务端错误，见 Error
+)
+
+// GenerateStreamEvent 流式生成事件
+type GenerateStreamEvent struct {
+	Type         string
+	Delta        string
+	Answer       string
+	FinishReason string
+	Usage        *TokenUsage
+	Error        string
+}
+
+// GenerateStream 流式生成结果
+type GenerateStream = Stream[GenerateStreamEvent]
+
 // Generate 生成答案（不检索，直接生成）
-func (s *GenerateService) Generate(ctx context.Context, req *GenerateRequest) (*GenerateResponse, error) {
+// req.Stream 为 true 时以流式方式请求，并在读取完整个流后返回汇总结果
+func (s *GenerateService) Generate(ctx context.Context, req *GenerateRequest, opts ...CallOption) (*GenerateResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
+	if req.Stream {
+		return s.generateStreamed(ctx, req, opts...)
+	}
+
 	var result GenerateResponse
-	err := s.client.do(ctx, "POST", "/api/v1/generate", req, &result)
+	op := &Operation{Service: "Generate", Method: "Generate", HTTPMethod: "POST", Path: "/api/v1/generate", DatasetID: req.DatasetID, Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
+
+// GenerateStream 以流式方式生成答案，文本逐段返回
+// 与 QAService.AskStream 使用相同的流格式，取消 ctx 会中断读取
+func (s *GenerateService) GenerateStream(ctx context.Context, req *GenerateRequest, opts ...CallOption) (*GenerateStream, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
+	body := *req
+	body.Stream = true
+
+	op := &Operation{Service: "Generate", Method: "GenerateStream", HTTPMethod: "POST", Path: "/api/v1/generate", DatasetID: req.DatasetID, Request: &body}
+	resp, err := s.client.openStream(ctx, op, &body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newStream[GenerateStreamEvent](ctx, resp, newTextStreamDecoder[GenerateStreamEvent](&generateStreamEvents{})), nil
+}
+
+// generateStreamed 读取整个流并汇总为 GenerateResponse
+func (s *GenerateService) generateStreamed(ctx context.Context, req *GenerateRequest, opts ...CallOption) (*GenerateResponse, error) {
+	stream, err := s.GenerateStream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var result GenerateResponse
+	for stream.Next() {
+		if ev := stream.Current(); ev.Type == GenerateEventDone {
+			result.Answer = ev.Answer
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// generateStreamEvents 将文本流事件映射为 GenerateStreamEvent，并记录结束原因和用量
+type generateStreamEvents struct {
+	finishReason string
+	usage        *TokenUsage
+}
+
+func (e *generateStreamEvents) extra(typ string, done bool, data []byte) ([]GenerateStreamEvent, bool) {
+	var p struct {
+		FinishReason string      `json:"finish_reason"`
+		Usage        *TokenUsage `json:"usage"`
+	}
+	_ = json.Unmarshal(data, &p)
+
+	// 部分服务端在最后一个增量中携带结束原因和用量
+	if p.FinishReason != "" {
+		e.finishReason = p.FinishReason
+	}
+	if p.Usage != nil {
+		e.usage = p.Usage
+	}
+	return nil, false
+}
+
+func (e *generateStreamEvents) response(data []byte) (string, []GenerateStreamEvent, error) {
+	var resp GenerateResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", nil, err
+	}
+	return resp.Answer, nil, nil
+}
+
+func (e *generateStreamEvents) deltaEvent(delta string) GenerateStreamEvent {
+	return GenerateStreamEvent{Type: GenerateEventDelta, Delta: delta}
+}
+
+func (e *generateStreamEvents) doneEvent(answer string) GenerateStreamEvent {
+	return GenerateStreamEvent{Type: GenerateEventDone, Answer: answer, FinishReason: e.finishReason, Usage: e.usage}
+}
+
+func (e *generateStreamEvents) errorEvent(msg string) GenerateStreamEvent {
+	return GenerateStreamEvent{Type: GenerateEventError, Error: msg}
+}
@@ -14,9 +14,10 @@ type HealthResponse struct {
 }
 
 // Check 健康检查
-func (s *HealthService) Check(ctx context.Context) (*HealthResponse, error) {
+func (s *HealthService) Check(ctx context.Context, opts ...CallOption) (*HealthResponse, error) {
 	var result HealthResponse
-	err := s.client.do(ctx, "GET", "/health", nil, &result)
+	op := &Operation{Service: "Health", Method: "Check", HTTPMethod: "GET", Path: "/health"}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
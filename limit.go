@@ -0,0 +1,126 @@
+package sdk
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+// OperationClass 限流和并发限制使用的操作分类
+type OperationClass string
+
+// 操作分类
+const (
+	ClassUpload   OperationClass = "upload"   // Documents.Upload
+	ClassSearch   OperationClass = "search"   // Search.Retrieve
+	ClassQA       OperationClass = "qa"       // QA.Ask、QA.AskStream
+	ClassGenerate OperationClass = "generate" // Generate.Generate、Generate.GenerateStream
+	ClassDefault  OperationClass = "default"  // 其他操作，例如列表、查询和删除
+)
+
+// Class 返回操作所属的分类
+func (op *Operation) Class() OperationClass {
+	switch {
+	case op.Service == "Documents" && op.Method == "Upload":
+		return ClassUpload
+	case op.Service == "Search":
+		return ClassSearch
+	case op.Service == "QA":
+		return ClassQA
+	case op.Service == "Generate":
+		return ClassGenerate
+	}
+	return ClassDefault
+}
+
+// budget 一组限流和并发限制，字段为 nil 表示不限制
+type budget struct {
+	rate     *rateLimiter
+	inFlight chan struct{}
+}
+
+// limits 客户端级别的预算和按分类配置的预算，两者同时生效
+type limits struct {
+	global  budget
+	classes map[OperationClass]*budget
+}
+
+// budgets 返回 classes 对应的预算，没有指定分类时返回全局预算
+func (c *Client) budgets(classes []OperationClass) []*budget {
+	if c.limits == nil {
+		c.limits = &limits{classes: make(map[OperationClass]*budget)}
+	}
+	if len(classes) == 0 {
+		return []*budget{&c.limits.global}
+	}
+
+	budgets := make([]*budget, 0, len(classes))
+	for _, class := range classes {
+		b := c.limits.classes[class]
+		if b == nil {
+			b = &budget{}
+			c.limits.classes[class] = b
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets
+}
+
+// acquire 在发送一次请求前等待并发名额和令牌，返回排队时间和释放并发名额的函数
+//
+// 先获取分类预算再获取全局预算，避免在等待分类名额时占用全局名额。
+// ctx 结束时归还已获取的名额并返回 ctx 的错误。
+func (l *limits) acquire(ctx context.Context, class OperationClass) (time.Duration, func(), error) {
+	start := time.Now()
+	budgets := []*budget{&l.global}
+	if b := l.classes[class]; b != nil {
+		budgets = []*budget{b, &l.global}
+	}
+
+	var held []chan struct{}
+	release := func() {
+		for _, sem := range held {
+			<-sem
+		}
+	}
+
+	for _, b := range budgets {
+		if b.inFlight == nil {
+			continue
+		}
+		select {
+		case b.inFlight <- struct{}{}:
+			held = append(held, b.inFlight)
+		case <-ctx.Done():
+			release()
+			return time.Since(start), nil, ctx.Err()
+		}
+	}
+	for _, b := range budgets {
+		if b.rate == nil {
+			continue
+		}
+		if _, err := b.rate.wait(ctx); err != nil {
+			release()
+			return time.Since(start), nil, err
+		}
+	}
+
+	var once sync.Once
+	return time.Since(start), func() { once.Do(release) }, nil
+}
+
+// releaseOnClose 在响应体关闭时调用 release，用于释放并发名额或取消单次调用的 ctx，
+// 流式响应因此会一直占用这些资源直到 Stream 被关闭
+type releaseOnClose struct {
+	io.ReadCloser
+	release func()
+}
+
+// Close 关闭响应体并调用 release
+func (b *releaseOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.release()
+	return err
+}
@@ -0,0 +1,267 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurstAndRefill(t *testing.T) {
+	l := newRateLimiter(10, 2)
+	ctx := context.Background()
+
+	// 桶满时不等待
+	for i := 0; i < 2; i++ {
+		if delay, err := l.wait(ctx); err != nil || delay != 0 {
+			t.Fatalf("wait %d = %s, %v, want no delay within the burst", i+1, delay, err)
+		}
+	}
+
+	// 时间流逝后按速率补充，且不超过桶容量
+	l.mu.Lock()
+	l.last = l.last.Add(-150 * time.Millisecond)
+	l.mu.Unlock()
+	if delay, err := l.wait(ctx); err != nil || delay != 0 {
+		t.Fatalf("wait after refill = %s, %v, want no delay", delay, err)
+	}
+	l.mu.Lock()
+	l.last = l.last.Add(-time.Hour)
+	l.mu.Unlock()
+	l.wait(ctx)
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < 0.99 || tokens > 1.01 {
+		t.Errorf("tokens after a long idle period and one wait = %.2f, want burst-1 = 1", tokens)
+	}
+
+	if got := newRateLimiter(1, 0).burst; got != 1 {
+		t.Errorf("burst for 0 = %v, want 1", got)
+	}
+}
+
+func TestRateLimiterDelay(t *testing.T) {
+	l := newRateLimiter(1000, 1)
+	ctx := context.Background()
+	l.wait(ctx)
+
+	// 桶空时按速率计算等待时间：1000 rps 约 1ms 一个令牌
+	delay, err := l.wait(ctx)
+	if err != nil || delay <= 0 || delay > time.Millisecond {
+		t.Errorf("wait with an empty bucket = %s, %v, want a delay of at most 1ms", delay, err)
+	}
+}
+
+func TestRateLimiterCancelReturnsToken(t *testing.T) {
+	l := newRateLimiter(0.001, 1)
+	l.wait(context.Background())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("wait = %v, want context.Canceled", err)
+	}
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.01 || tokens > 0.01 {
+		t.Errorf("tokens after a canceled wait = %.3f, want the reservation returned", tokens)
+	}
+}
+
+// newTestLimits 构造全局和 search 分类的并发限制
+func newTestLimits(global, search int) *limits {
+	l := &limits{classes: map[OperationClass]*budget{}}
+	if global > 0 {
+		l.global.inFlight = make(chan struct{}, global)
+	}
+	if search > 0 {
+		l.classes[ClassSearch] = &budget{inFlight: make(chan struct{}, search)}
+	}
+	return l
+}
+
+func TestLimitsPerClass(t *testing.T) {
+	l := newTestLimits(2, 1)
+	ctx := context.Background()
+
+	_, releaseSearch, err := l.acquire(ctx, ClassSearch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.global.inFlight) != 1 || len(l.classes[ClassSearch].inFlight) != 1 {
+		t.Fatalf("in flight = global %d, search %d, want both held", len(l.global.inFlight), len(l.classes[ClassSearch].inFlight))
+	}
+
+	// search 名额已满，其他分类只受全局限制
+	_, releaseDefault, err := l.acquire(ctx, ClassDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocked, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if _, _, err := l.acquire(blocked, ClassSearch); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("second search acquire = %v, want it queued until the deadline", err)
+	}
+
+	// 释放函数可以重复调用，只归还一次
+	releaseSearch()
+	releaseSearch()
+	if len(l.global.inFlight) != 1 || len(l.classes[ClassSearch].inFlight) != 0 {
+		t.Errorf("in flight after release = global %d, search %d, want 1 and 0", len(l.global.inFlight), len(l.classes[ClassSearch].inFlight))
+	}
+	releaseDefault()
+	if len(l.global.inFlight) != 0 {
+		t.Errorf("global in flight = %d, want 0", len(l.global.inFlight))
+	}
+}
+
+func TestLimitsCancelWhileQueued(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(l *limits)
+	}{
+		{"waiting for the global slot", func(l *limits) {
+			// 全局名额被占满，search 已经拿到分类名额
+			l.global.inFlight <- struct{}{}
+		}},
+		{"waiting for a rate token", func(l *limits) {
+			l.global.rate = newRateLimiter(0.001, 1)
+			l.global.rate.wait(context.Background())
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := newTestLimits(1, 1)
+			tt.setup(l)
+			heldGlobal := len(l.global.inFlight)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			var queued time.Duration
+			go func() {
+				var err error
+				queued, _, err = l.acquire(ctx, ClassSearch)
+				done <- err
+			}()
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+
+			if err := <-done; !errors.Is(err, context.Canceled) {
+				t.Fatalf("acquire = %v, want context.Canceled", err)
+			}
+			if queued <= 0 {
+				t.Errorf("queued = %s, want the time spent waiting", queued)
+			}
+			// 已获取的名额全部归还
+			if got := len(l.classes[ClassSearch].inFlight); got != 0 {
+				t.Errorf("search in flight = %d, want 0", got)
+			}
+			if got := len(l.global.inFlight); got != heldGlobal {
+				t.Errorf("global in flight = %d, want %d", got, heldGlobal)
+			}
+		})
+	}
+}
+
+func TestClientReleasesLimits(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/qa":
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "data: {\"delta\":\"a\"}\n\ndata: [DONE]\n\n")
+		case "/api/v1/datasets/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"success":false,"message":"boom"}`)
+		default:
+			io.WriteString(w, `{"success":true,"data":{"id":"ds-1"}}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	client := newTestClient(t, srv.URL, WithMaxInFlight(1), WithRetryPolicy(nil))
+	ctx := context.Background()
+	inFlight := func() int { return len(client.limits.global.inFlight) }
+
+	if err := getDataset(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if got := inFlight(); got != 0 {
+		t.Errorf("in flight after a JSON call = %d, want 0", got)
+	}
+
+	if _, err := client.Datasets.Get(ctx, "broken"); err == nil {
+		t.Fatal("Get succeeded, want the 500 error")
+	}
+	if got := inFlight(); got != 0 {
+		t.Errorf("in flight after an error = %d, want 0", got)
+	}
+
+	// 流式响应在 Close 之前一直占用名额
+	stream, err := client.QA.AskStream(ctx, &QARequest{DatasetID: "ds-1", Query: "q"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for stream.Next() {
+	}
+	if got := inFlight(); got != 1 {
+		t.Errorf("in flight while the stream is open = %d, want 1", got)
+	}
+	stream.Close()
+	if got := inFlight(); got != 0 {
+		t.Errorf("in flight after Close = %d, want 0", got)
+	}
+}
+
+func TestClientQueueTime(t *testing.T) {
+	srv := newScriptedServer(t, http.StatusServiceUnavailable)
+	var queueTime time.Duration
+	client := newTestClient(t, srv.URL,
+		WithMaxInFlight(1),
+		WithRetryPolicy(fastRetryPolicy(1)),
+		WithMiddleware(func(next Invoker) Invoker {
+			return func(ctx context.Context, op *Operation) error {
+				err := next(ctx, op)
+				queueTime = op.QueueTime
+				return err
+			}
+		}),
+	)
+	ctx := context.Background()
+
+	// 先占用唯一的名额，请求需要排队
+	_, release, err := client.limits.acquire(ctx, ClassDefault)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	time.AfterFunc(50*time.Millisecond, release)
+
+	if err := getDataset(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); queueTime < 40*time.Millisecond || queueTime > elapsed {
+		t.Errorf("QueueTime = %s, want about 50ms of waiting within the %s call", queueTime, elapsed)
+	}
+	if got := srv.count(); got != 2 {
+		t.Errorf("requests = %d, want the 503 and its retry", got)
+	}
+
+	// 排队时 ctx 结束，请求不会发出
+	_, release, _ = client.limits.acquire(ctx, ClassDefault)
+	defer release()
+	timeout, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := getDataset(timeout, client); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded while queued", err)
+	}
+	if queueTime < 10*time.Millisecond {
+		t.Errorf("QueueTime of the canceled call = %s, want the time spent queued", queueTime)
+	}
+	if got := srv.count(); got != 2 {
+		t.Errorf("requests = %d, want no request sent while queued", got)
+	}
+}
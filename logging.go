@@ -0,0 +1,257 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// redacted 日志中替换敏感信息的占位符
+const redacted = "[REDACTED]"
+
+// maxLoggedBody debug 日志中请求体和响应体的最大长度
+const maxLoggedBody = 2048
+
+// SensitiveHeaders 日志中需要脱敏的请求头和响应头，cassette 包录制时使用同一份列表
+var SensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"}
+
+// SensitiveFields 日志中需要脱敏的 JSON 字段，按名称匹配且不区分大小写，cassette 包录制时使用同一份列表
+//
+// 覆盖 AIModelConfig.APIKey、CustomHeaders 和 OAuth2 凭据；值为对象时保留键名、替换每个值，
+// 因此 custom_headers 中的请求头名称仍然可见。
+var SensitiveFields = []string{
+	"api_key",
+	"apikey",
+	"custom_headers",
+	"access_token",
+	"refresh_token",
+	"client_secret",
+	"password",
+	"secret",
+	"token",
+}
+
+// requestIDHeaders 用于关联服务端日志的请求 ID 响应头，按顺序查找
+var requestIDHeaders = []string{headerRequestID, "X-Trace-Id"}
+
+// logAttempt 记录一次 HTTP 尝试
+//
+// 成功的响应按 logLevel 记录，非 2xx 响应和连接失败按 Warn 记录；
+// logger 启用 Debug 时额外记录脱敏后的请求头、请求体和截断的响应体。
+func (c *Client) logAttempt(ctx context.Context, req *http.Request, resp *http.Response, err error, attempt int, elapsed, queued time.Duration) {
+	level := c.logLevel
+	if err != nil || resp.StatusCode >= 300 {
+		level = slog.LevelWarn
+	}
+	debug := c.logger.Enabled(ctx, slog.LevelDebug)
+	if !debug && !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.URL.RequestURI()),
+		slog.Duration("duration", elapsed),
+	}
+	if len(c.endpoints) > 1 {
+		attrs = append(attrs, slog.String("endpoint", req.URL.Scheme+"://"+req.URL.Host))
+	}
+	if queued > 0 {
+		attrs = append(attrs, slog.Duration("queued", queued))
+	}
+	if attempt > 0 {
+		attrs = append(attrs, slog.Int("attempt", attempt+1))
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	} else {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+	}
+	if id := requestID(req, resp); id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+
+	if debug {
+		attrs = append(attrs, slog.Any("request_headers", redactHeader(req.Header)))
+		if body, ok := peekRequestBody(req); ok {
+			attrs = append(attrs, slog.String("request_body", body))
+		}
+		if resp != nil {
+			if body, ok := peekResponseBody(resp); ok {
+				attrs = append(attrs, slog.String("response_body", body))
+			}
+		}
+	}
+
+	c.logger.LogAttrs(ctx, level, "raglite request", attrs...)
+}
+
+// requestID 优先使用服务端返回的请求 ID，其次是调用方设置的请求头
+func requestID(req *http.Request, resp *http.Response) string {
+	for _, name := range requestIDHeaders {
+		if resp != nil {
+			if id := resp.Header.Get(name); id != "" {
+				return id
+			}
+		}
+		if id := req.Header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// redactHeader 返回脱敏后的请求头副本
+func redactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, name := range SensitiveHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	return h
+}
+
+// peekRequestBody 通过 GetBody 读取请求体副本，不影响实际发送的请求；
+// 只记录 JSON 请求体，上传的文件内容不记录，也不会为此重新读取文件
+func peekRequestBody(req *http.Request) (string, bool) {
+	if req.GetBody == nil || req.ContentLength == 0 {
+		return "", false
+	}
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType != "application/json" {
+		return "", false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return "", false
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", false
+	}
+	return formatBody(data), true
+}
+
+// peekResponseBody 读取响应体后放回，流式响应不记录
+func peekResponseBody(resp *http.Response) (string, bool) {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "text/event-stream" || mediaType == "application/x-ndjson" {
+		return "", false
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		return "", false
+	}
+	return formatBody(data), true
+}
+
+// formatBody 先脱敏再截断 body，避免截断后的 JSON 无法解析而漏掉敏感字段
+func formatBody(data []byte) string {
+	size := len(data)
+	data = redactJSON(data)
+	if len(data) <= maxLoggedBody {
+		return string(data)
+	}
+
+	data = data[:maxLoggedBody]
+	for len(data) > 0 && !utf8.Valid(data) {
+		data = data[:len(data)-1]
+	}
+	return fmt.Sprintf("%s...(truncated, %d bytes total)", data, size)
+}
+
+// redactJSON 将 JSON 中 SensitiveFields 的值替换为占位符，非 JSON 内容原样返回
+func redactJSON(data []byte) []byte {
+	if !json.Valid(data) {
+		return data
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || !redactValue(v) {
+		return data
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return data
+	}
+	return out
+}
+
+// redactValue 递归脱敏，返回是否有修改
+func redactValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if isSensitiveField(key) {
+				if value, ok := redactField(child); ok {
+					v[key] = value
+					changed = true
+				}
+				continue
+			}
+			changed = redactValue(child) || changed
+		}
+	case []interface{}:
+		for _, child := range v {
+			changed = redactValue(child) || changed
+		}
+	}
+	return changed
+}
+
+// isSensitiveField 字段名是否在 SensitiveFields 中
+func isSensitiveField(key string) bool {
+	for _, name := range SensitiveFields {
+		if strings.EqualFold(key, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactField 返回敏感字段脱敏后的值：对象保留键名、替换每个值，空字符串和空对象不需要脱敏
+func redactField(v interface{}) (interface{}, bool) {
+	switch v := v.(type) {
+	case string:
+		return redacted, v != ""
+	case map[string]interface{}:
+		for name := range v {
+			v[name] = redacted
+		}
+		return v, len(v) > 0
+	default:
+		return redacted, v != nil
+	}
+}
+
+// LogValue 实现 slog.LogValuer，记录日志时隐藏 APIKey 和 CustomHeaders 的值
+func (c AIModelConfig) LogValue() slog.Value {
+	type plain AIModelConfig
+	p := plain(c)
+	if p.APIKey != "" {
+		p.APIKey = redacted
+	}
+	if len(p.CustomHeaders) > 0 {
+		headers := make(map[string]string, len(p.CustomHeaders))
+		for name := range p.CustomHeaders {
+			headers[name] = redacted
+		}
+		p.CustomHeaders = headers
+	}
+	return slog.AnyValue(p)
+}
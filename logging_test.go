@@ -0,0 +1,165 @@
+package sdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// secrets 测试中不允许出现在日志里的值
+var secrets = []string{"sk-secret", "tenant-token", "header-secret", "session=abc"}
+
+// assertNoSecrets 检查输出中没有任何 secrets
+func assertNoSecrets(t *testing.T, output string) {
+	t.Helper()
+	for _, s := range secrets {
+		if strings.Contains(output, s) {
+			t.Errorf("output leaks %q:\n%s", s, output)
+		}
+	}
+}
+
+func TestRedactHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer sk-secret")
+	header.Set("X-Api-Key", "sk-secret")
+	header.Set("Cookie", "session=abc")
+	header.Set("Content-Type", "application/json")
+
+	got := redactHeader(header)
+	for _, name := range []string{"Authorization", "X-Api-Key", "Cookie"} {
+		if got.Get(name) != redacted {
+			t.Errorf("%s = %q, want %s", name, got.Get(name), redacted)
+		}
+	}
+	if got.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q, want it kept", got.Get("Content-Type"))
+	}
+	if got.Get("Proxy-Authorization") != "" {
+		t.Errorf("Proxy-Authorization = %q, want absent headers left absent", got.Get("Proxy-Authorization"))
+	}
+	if header.Get("Authorization") != "Bearer sk-secret" {
+		t.Error("redactHeader modified the original header")
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{
+			name: "top-level api_key",
+			in:   `{"api_key":"sk-secret","name":"m"}`,
+			want: `{"api_key":"[REDACTED]","name":"m"}`,
+		},
+		{
+			name: "nested config and custom_headers",
+			in:   `{"config":{"apiKey":"sk-secret","custom_headers":{"X-Tenant":"tenant-token"},"timeout":30}}`,
+			want: `{"config":{"apiKey":"[REDACTED]","custom_headers":{"X-Tenant":"[REDACTED]"},"timeout":30}}`,
+		},
+		{
+			name: "inside an envelope array",
+			in:   `{"data":{"models":[{"config":{"API_KEY":"sk-secret"}}]}}`,
+			want: `{"data":{"models":[{"config":{"API_KEY":"[REDACTED]"}}]}}`,
+		},
+		{
+			name: "empty api_key is kept",
+			in:   `{"api_key": ""}`,
+			want: `{"api_key": ""}`,
+		},
+		{
+			name: "no secrets keeps the original bytes",
+			in:   `{"query": "q", "top_k": 5}`,
+			want: `{"query": "q", "top_k": 5}`,
+		},
+		{
+			name: "large numbers survive",
+			in:   `{"api_key":"sk-secret","total":12345678901234567890}`,
+			want: `{"api_key":"[REDACTED]","total":12345678901234567890}`,
+		},
+		{
+			name: "non-JSON is returned as is",
+			in:   `api_key=sk-secret`,
+			want: `api_key=sk-secret`,
+		},
+	}
+	for _, tt := range tests {
+		if got := string(redactJSON([]byte(tt.in))); got != tt.want {
+			t.Errorf("%s: redactJSON() = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormatBodyRedactsBeforeTruncating(t *testing.T) {
+	body := `{"padding":"` + strings.Repeat("x", 3*maxLoggedBody) + `","api_key":"sk-secret"}`
+	got := formatBody([]byte(body))
+	assertNoSecrets(t, got)
+	if !strings.HasSuffix(got, "...(truncated, "+strconv.Itoa(len(body))+" bytes total)") {
+		t.Errorf("formatBody() = ...%s, want the truncation marker with the original size", got[len(got)-40:])
+	}
+}
+
+func TestAIModelConfigLogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	config := AIModelConfig{
+		APIKey:        "sk-secret",
+		APIBase:       "https://api.example.com",
+		CustomHeaders: map[string]string{"X-Tenant": "tenant-token"},
+	}
+
+	logger.Info("model", "config", config, "ptr", &config)
+	out := buf.String()
+	assertNoSecrets(t, out)
+	if !strings.Contains(out, "https://api.example.com") || !strings.Contains(out, "X-Tenant") {
+		t.Errorf("output = %s, want non-secret fields and header names kept", out)
+	}
+	if config.APIKey != "sk-secret" || config.CustomHeaders["X-Tenant"] != "tenant-token" {
+		t.Error("LogValue modified the config")
+	}
+}
+
+func TestLoggerRedactsRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 回显请求体，响应中的密钥同样需要脱敏
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Write([]byte(`{"success":true,"data":` + string(body) + `}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := newTestClient(t, srv.URL, WithAPIKey("sk-secret"), WithLogger(logger))
+
+	_, err := client.Models.Create(context.Background(), &CreateModelRequest{
+		Name:      "chat",
+		ModelType: "chat",
+		Provider:  "openai",
+		ModelName: "gpt-4o",
+		Config: AIModelConfig{
+			APIKey:        "sk-secret",
+			CustomHeaders: map[string]string{"X-Tenant": "tenant-token", "X-Extra": "header-secret"},
+		},
+	}, WithHeader("X-Api-Key", "sk-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	assertNoSecrets(t, out)
+	for _, want := range []string{"request_headers", "request_body", "response_body", "X-Tenant", "gpt-4o"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output is missing %q:\n%s", want, out)
+		}
+	}
+}
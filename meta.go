@@ -0,0 +1,83 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// headerRequestID 请求 ID 请求头，每个请求都会携带，重试时保持不变
+const headerRequestID = "X-Request-Id"
+
+// ResponseMeta 一次调用的响应元数据，通过 WithResponseMeta 获取，调用失败时同样会填充
+type ResponseMeta struct {
+	// 最后一次尝试的状态码，没有收到响应时为 0
+	StatusCode int
+
+	// 最后一次收到的响应头
+	Header http.Header
+
+	// 服务端返回的请求 ID，服务端没有返回时为客户端发送的请求 ID
+	RequestID string
+
+	// 从调用开始到收到最后一次响应的时间，包括排队和重试；流式调用只计算到连接建立
+	Duration time.Duration
+
+	// 重试次数，不含首次请求
+	Retries int
+}
+
+// requestIDKey 保存请求 ID 的 context key
+type requestIDKey struct{}
+
+// ContextWithRequestID 返回携带请求 ID 的 context，使用该 context 的调用以 id 作为 X-Request-Id，
+// 适合将上游服务收到的请求 ID 传递给 RAGLite
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext 返回 ContextWithRequestID 设置的请求 ID，没有时返回空字符串
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// prepare 在中间件之前准备一次调用：应用调用选项、设置请求 ID 和单次调用的超时
+//
+// 请求 ID 依次取自 WithRequestID、ContextWithRequestID，都没有时随机生成。
+func (c *Client) prepare(ctx context.Context, op *Operation, opts []CallOption) (context.Context, context.CancelFunc) {
+	op.apply(opts)
+	op.started = time.Now()
+	if op.Header.Get(headerRequestID) == "" {
+		id := RequestIDFromContext(ctx)
+		if id == "" {
+			id = randomID()
+		}
+		op.SetHeader(headerRequestID, id)
+	}
+	return callContext(ctx, op)
+}
+
+// ResponseMeta 返回 WithResponseMeta 设置的 meta，没有时返回 nil，
+// 供 mock 等替代实现填充响应元数据
+func (op *Operation) ResponseMeta() *ResponseMeta {
+	return op.meta
+}
+
+// recordAttempt 记录一次尝试的结果，配置了 WithResponseMeta 时更新响应元数据
+func (op *Operation) recordAttempt(req *http.Request, resp *http.Response) {
+	op.attempts++
+	meta := op.meta
+	if meta == nil {
+		return
+	}
+	meta.Retries = op.attempts - 1
+	meta.RequestID = requestID(req, resp)
+	if resp != nil {
+		meta.StatusCode = resp.StatusCode
+		meta.Header = resp.Header.Clone()
+	}
+	if !op.started.IsZero() {
+		meta.Duration = time.Since(op.started)
+	}
+}
@@ -0,0 +1,167 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestResponseMetaAfterRetry(t *testing.T) {
+	srv := newScriptedServer(t, http.StatusServiceUnavailable)
+	srv.header = http.Header{headerRequestID: {"srv-1"}, "X-Served-By": {"node-a"}}
+	client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(2)))
+
+	var meta ResponseMeta
+	start := time.Now()
+	if _, err := client.Datasets.Get(context.Background(), "ds-1", WithResponseMeta(&meta)); err != nil {
+		t.Fatal(err)
+	}
+	elapsed := time.Since(start)
+
+	if meta.StatusCode != http.StatusOK || meta.Retries != 1 || meta.RequestID != "srv-1" {
+		t.Errorf("meta = status %d, retries %d, request ID %q, want 200, 1, srv-1", meta.StatusCode, meta.Retries, meta.RequestID)
+	}
+	if meta.Header.Get("X-Served-By") != "node-a" {
+		t.Errorf("Header = %v, want the last response headers", meta.Header)
+	}
+	if meta.Duration <= 0 || meta.Duration > elapsed {
+		t.Errorf("Duration = %s, want within the %s call", meta.Duration, elapsed)
+	}
+}
+
+func TestResponseMetaRequestID(t *testing.T) {
+	idPattern := regexp.MustCompile(`^[0-9a-f]{32}$`)
+	tests := []struct {
+		name string
+		ctx  context.Context
+		opts []CallOption
+		want string // 空字符串表示随机生成
+	}{
+		{"WithRequestID", context.Background(), []CallOption{WithRequestID("client-1")}, "client-1"},
+		{"ContextWithRequestID", ContextWithRequestID(context.Background(), "upstream-1"), nil, "upstream-1"},
+		{"WithRequestID overrides the context", ContextWithRequestID(context.Background(), "upstream-1"), []CallOption{WithRequestID("client-1")}, "client-1"},
+		{"generated", context.Background(), nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 服务端不返回请求 ID 时使用客户端发送的请求 ID，重试时保持不变
+			srv := newScriptedServer(t, http.StatusBadGateway)
+			client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(1)))
+
+			var meta ResponseMeta
+			if _, err := client.Datasets.Get(tt.ctx, "ds-1", append(tt.opts, WithResponseMeta(&meta))...); err != nil {
+				t.Fatal(err)
+			}
+			if tt.want != "" && meta.RequestID != tt.want {
+				t.Errorf("RequestID = %q, want %q", meta.RequestID, tt.want)
+			}
+			if tt.want == "" && !idPattern.MatchString(meta.RequestID) {
+				t.Errorf("RequestID = %q, want a random 32-character hex ID", meta.RequestID)
+			}
+			for i, req := range srv.received() {
+				if got := req.Header.Get(headerRequestID); got != meta.RequestID {
+					t.Errorf("attempt %d X-Request-Id = %q, want %q", i+1, got, meta.RequestID)
+				}
+			}
+		})
+	}
+}
+
+func TestResponseMetaOnFailure(t *testing.T) {
+	t.Run("API error", func(t *testing.T) {
+		srv := newScriptedServer(t, 503, 503, 503)
+		client := newTestClient(t, srv.URL, WithRetryPolicy(fastRetryPolicy(2)))
+
+		var meta ResponseMeta
+		_, err := client.Datasets.Get(context.Background(), "ds-1", WithRequestID("req-1"), WithResponseMeta(&meta))
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("err = %v, want *APIError", err)
+		}
+		if meta.StatusCode != 503 || meta.Retries != 2 || meta.RequestID != "req-1" || apiErr.RequestID != meta.RequestID {
+			t.Errorf("meta = status %d, retries %d, request ID %q (error %q), want 503, 2, req-1",
+				meta.StatusCode, meta.Retries, meta.RequestID, apiErr.RequestID)
+		}
+	})
+
+	t.Run("no response", func(t *testing.T) {
+		client := newTestClient(t, closedServerURL(), WithRetryPolicy(fastRetryPolicy(1)))
+
+		var meta ResponseMeta
+		if _, err := client.Datasets.Get(context.Background(), "ds-1", WithResponseMeta(&meta)); err == nil {
+			t.Fatal("Get succeeded against a closed server")
+		}
+		if meta.StatusCode != 0 || meta.Header != nil || meta.Retries != 1 || meta.RequestID == "" {
+			t.Errorf("meta = %+v, want no status or headers, 1 retry and the client request ID", meta)
+		}
+	})
+}
+
+func TestResponseMetaDuration(t *testing.T) {
+	const delay = 30 * time.Millisecond
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		if r.URL.Path == "/api/v1/qa" {
+			w.Header().Set("Content-Type", "text/event-stream")
+			w.(http.Flusher).Flush()
+			// 流式响应的内容在连接建立后才到达，不计入 Duration
+			time.Sleep(delay)
+			io.WriteString(w, "data: [DONE]\n\n")
+			return
+		}
+		io.WriteString(w, `{"success":true,"data":{"id":"ds-1"}}`)
+	}))
+	t.Cleanup(srv.Close)
+	client := newTestClient(t, srv.URL)
+	ctx := context.Background()
+
+	var meta ResponseMeta
+	if _, err := client.Datasets.Get(ctx, "ds-1", WithResponseMeta(&meta)); err != nil {
+		t.Fatal(err)
+	}
+	if meta.Duration < delay {
+		t.Errorf("Duration = %s, want at least the %s server latency", meta.Duration, delay)
+	}
+
+	var streamMeta ResponseMeta
+	start := time.Now()
+	stream, err := client.QA.AskStream(ctx, &QARequest{DatasetID: "ds-1", Query: "q"}, WithResponseMeta(&streamMeta))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for stream.Next() {
+	}
+	stream.Close()
+	total := time.Since(start)
+	if streamMeta.Duration < delay || streamMeta.Duration >= total {
+		t.Errorf("stream Duration = %s, want from %s up to connection setup, less than the %s total", streamMeta.Duration, delay, total)
+	}
+}
+
+func TestRequestCallOptions(t *testing.T) {
+	var meta ResponseMeta
+	opts := []CallOption{WithIdempotencyKey("batch"), WithResponseMeta(&meta)}
+
+	var op Operation
+	op.apply(requestCallOptions(opts, "3"))
+	if got := op.Header.Get(headerIdempotencyKey); got != "batch-3" {
+		t.Errorf("Idempotency-Key = %q, want batch-3", got)
+	}
+	if responseMetaOf(opts) != &meta {
+		t.Error("responseMetaOf did not return the WithResponseMeta pointer")
+	}
+
+	// 没有幂等键时原样返回
+	plain := []CallOption{WithHeader("X-Tenant", "acme")}
+	if got := requestCallOptions(plain, "1"); len(got) != 1 {
+		t.Errorf("options = %d, want the original option only", len(got))
+	}
+	if responseMetaOf(plain) != nil {
+		t.Error("responseMetaOf = non-nil without WithResponseMeta")
+	}
+}
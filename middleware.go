@@ -0,0 +1,87 @@
+package sdk
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// Operation 一次 SDK 操作的描述，中间件可以读取或修改其中的字段
+type Operation struct {
+	// 服务名，例如 "Documents"
+	Service string
+
+	// 方法名，例如 "Upload"
+	Method string
+
+	// HTTP 方法和请求路径（包含查询参数）
+	HTTPMethod string
+	Path       string
+
+	// 操作涉及的数据集 ID，没有时为空
+	DatasetID string
+
+	// 调用方传入的请求，例如 *RetrieveRequest；只有路径参数的操作为 nil
+	// 仅供中间件读取：请求体和查询参数在中间件执行之前已经确定，修改其字段不保证影响实际发送的请求，
+	// 需要改写请求时修改 Path 或 Header
+	Request interface{}
+
+	// 追加的请求头，可以在调用 next 之前设置，例如租户标识
+	Header http.Header
+
+	// 响应数据，next 成功返回后为解码后的结果（例如 *SearchResponse），
+	// 没有响应数据的操作（例如 Delete）和流式操作为 nil
+	Result interface{}
+
+	// Stream 为 true 时表示流式操作，next 返回时只完成了连接的建立
+	Stream bool
+
+	// 本次调用的超时时间，由 WithCallTimeout 设置，0 表示只受客户端超时和 ctx 限制
+	Timeout time.Duration
+
+	// 在客户端限流和并发限制上排队等待的总时间（包括重试），next 返回后可用
+	QueueTime time.Duration
+
+	meta     *ResponseMeta
+	started  time.Time
+	attempts int
+}
+
+// FullMethod 返回 "服务名.方法名"，例如 "Documents.Upload"
+func (op *Operation) FullMethod() string {
+	return op.Service + "." + op.Method
+}
+
+// SetHeader 设置追加的请求头
+func (op *Operation) SetHeader(key, value string) {
+	if op.Header == nil {
+		op.Header = http.Header{}
+	}
+	op.Header.Set(key, value)
+}
+
+// Invoker 执行一次操作
+type Invoker func(ctx context.Context, op *Operation) error
+
+// Middleware 包装 Invoker，可以在调用 next 前后观察或修改操作、结果和错误：
+//
+//	func timing(next sdk.Invoker) sdk.Invoker {
+//		return func(ctx context.Context, op *sdk.Operation) error {
+//			start := time.Now()
+//			err := next(ctx, op)
+//			log.Printf("%s took %s, err=%v", op.FullMethod(), time.Since(start), err)
+//			return err
+//		}
+//	}
+//
+// 中间件包裹的是整个逻辑操作，自动重试发生在 next 内部。
+type Middleware func(next Invoker) Invoker
+
+// invoke 依次经过中间件执行操作，final 负责实际的 HTTP 调用
+func (c *Client) invoke(ctx context.Context, op *Operation, final Invoker) error {
+	h := final
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		h = c.middlewares[i](h)
+	}
+	return h(ctx, op)
+}
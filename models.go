@@ -22,6 +22,20 @@ type CreateModelRequest struct {
 	IsDefault    bool              `json:"is_default,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *CreateModelRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("name", r.Name)
+	v.required("model_type", r.ModelType)
+	v.required("provider", r.Provider)
+	v.required("model_name", r.ModelName)
+	v.modelConfig("config.", &r.Config)
+	return v.err()
+}
+
 // UpdateModelRequest 更新模型请求
 type UpdateModelRequest struct {
 	Name         *string            `json:"name,omitempty"`
@@ -35,17 +49,46 @@ type UpdateModelRequest struct {
 	IsActive     *bool              `json:"is_active,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *UpdateModelRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.notEmpty("name", r.Name)
+	v.notEmpty("provider", r.Provider)
+	v.notEmpty("model_name", r.ModelName)
+	if r.Config != nil {
+		v.modelConfig("config.", r.Config)
+	}
+	return v.err()
+}
+
 // ListModelsRequest 列表查询请求
 type ListModelsRequest struct {
 	ModelType string
 	Provider  string
 	Status    string
+	Page      int // 页码，默认 1
+	PageSize  int // 每页数量，为 0 时使用服务端默认值，设为 PageSizeAll 则不分页
+}
+
+// Validate 校验请求参数，nil 表示使用默认值
+func (r *ListModelsRequest) Validate() error {
+	if r == nil {
+		return nil
+	}
+	var v validation
+	v.page(r.Page, r.PageSize)
+	return v.err()
 }
 
 // ListModelsResponse 列表响应
 type ListModelsResponse struct {
-	Models []AIModel `json:"models"`
-	Total  int64     `json:"total"`
+	Models   []AIModel `json:"models"`
+	Total    int64     `json:"total"`
+	Page     int       `json:"page"`
+	PageSize int       `json:"page_size"`
 }
 
 // ListProviderModelsRequest 获取供应商支持的模型列表请求
@@ -54,6 +97,16 @@ type ListProviderModelsRequest struct {
 	Options  map[string]interface{} `json:"options,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *ListProviderModelsRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("provider", r.Provider)
+	return v.err()
+}
+
 // CheckModelRequest 检查模型配置请求
 type CheckModelRequest struct {
 	Provider  string        `json:"provider"`
@@ -61,6 +114,18 @@ type CheckModelRequest struct {
 	Config    AIModelConfig `json:"config"`
 }
 
+// Validate 校验请求参数
+func (r *CheckModelRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("provider", r.Provider)
+	v.required("model_name", r.ModelName)
+	v.modelConfig("config.", &r.Config)
+	return v.err()
+}
+
 // UpsertModelRequest 根据配置创建或更新模型请求
 type UpsertModelRequest struct {
 	Name         string            `json:"name,omitempty"`
@@ -74,6 +139,19 @@ type UpsertModelRequest struct {
 	IsActive     bool              `json:"is_active,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *UpsertModelRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("model_type", r.ModelType)
+	v.required("provider", r.Provider)
+	v.required("model_name", r.ModelName)
+	v.modelConfig("config.", &r.Config)
+	return v.err()
+}
+
 // UpsertModelResponse Upsert 响应
 type UpsertModelResponse struct {
 	Action string  `json:"action"` // "created" 或 "updated"
@@ -81,9 +159,14 @@ type UpsertModelResponse struct {
 }
 
 // Create 创建 AI 模型
-func (s *ModelsService) Create(ctx context.Context, req *CreateModelRequest) (*AIModel, error) {
+func (s *ModelsService) Create(ctx context.Context, req *CreateModelRequest, opts ...CallOption) (*AIModel, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result AIModel
-	err := s.client.do(ctx, "POST", "/api/v1/models", req, &result)
+	op := &Operation{Service: "Models", Method: "Create", HTTPMethod: "POST", Path: "/api/v1/models", Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +174,11 @@ func (s *ModelsService) Create(ctx context.Context, req *CreateModelRequest) (*A
 }
 
 // List 列出 AI 模型
-func (s *ModelsService) List(ctx context.Context, req *ListModelsRequest) (*ListModelsResponse, error) {
+func (s *ModelsService) List(ctx context.Context, req *ListModelsRequest, opts ...CallOption) (*ListModelsResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	params := make(map[string]string)
 	if req != nil {
 		if req.ModelType != "" {
@@ -103,22 +190,47 @@ func (s *ModelsService) List(ctx context.Context, req *ListModelsRequest) (*List
 		if req.Status != "" {
 			params["status"] = req.Status
 		}
+		setPageParams(params, req.Page, req.PageSize)
 	}
 
 	path := s.client.buildURL("/api/v1/models", params)
 	var result ListModelsResponse
-	err := s.client.do(ctx, "GET", path, nil, &result)
+	op := &Operation{Service: "Models", Method: "List", HTTPMethod: "GET", Path: path, Request: req}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
+// ListPager 返回逐页列出模型的迭代器，req 可以为 nil，其中的 Page 和 PageSize 会被忽略，callOpts 作用于每一页的请求
+func (s *ModelsService) ListPager(req *ListModelsRequest, opts *PagerOptions, callOpts ...CallOption) *Pager[AIModel] {
+	return NewPager(func(ctx context.Context, page, pageSize int) ([]AIModel, int64, error) {
+		var pageReq ListModelsRequest
+		if req != nil {
+			pageReq = *req
+		}
+		pageReq.Page = page
+		pageReq.PageSize = pageSize
+
+		resp, err := s.List(ctx, &pageReq, callOpts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Models, resp.Total, nil
+	}, opts)
+}
+
 // Get 获取模型详情
-func (s *ModelsService) Get(ctx context.Context, modelID string) (*AIModel, error) {
+func (s *ModelsService) Get(ctx context.Context, modelID string, opts ...CallOption) (*AIModel, error) {
+	if err := s.client.validateIDs("model_id", modelID); err != nil {
+		return nil, err
+	}
+
 	var result AIModel
 	path := fmt.Sprintf("/api/v1/models/%s", modelID)
-	err := s.client.do(ctx, "GET", path, nil, &result)
+	op := &Operation{Service: "Models", Method: "Get", HTTPMethod: "GET", Path: path}
+	err := s.client.do(ctx, op, nil, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -126,10 +238,18 @@ func (s *ModelsService) Get(ctx context.Context, modelID string) (*AIModel, erro
 }
 
 // Update 更新模型
-func (s *ModelsService) Update(ctx context.Context, modelID string, req *UpdateModelRequest) (*AIModel, error) {
+func (s *ModelsService) Update(ctx context.Context, modelID string, req *UpdateModelRequest, opts ...CallOption) (*AIModel, error) {
+	if err := s.client.validateIDs("model_id", modelID); err != nil {
+		return nil, err
+	}
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result AIModel
 	path := fmt.Sprintf("/api/v1/models/%s", modelID)
-	err := s.client.do(ctx, "PUT", path, req, &result)
+	op := &Operation{Service: "Models", Method: "Update", HTTPMethod: "PUT", Path: path, Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -137,15 +257,25 @@ func (s *ModelsService) Update(ctx context.Context, modelID string, req *UpdateM
 }
 
 // Delete 删除模型
-func (s *ModelsService) Delete(ctx context.Context, modelID string) error {
+func (s *ModelsService) Delete(ctx context.Context, modelID string, opts ...CallOption) error {
+	if err := s.client.validateIDs("model_id", modelID); err != nil {
+		return err
+	}
+
 	path := fmt.Sprintf("/api/v1/models/%s", modelID)
-	return s.client.do(ctx, "DELETE", path, nil, nil)
+	op := &Operation{Service: "Models", Method: "Delete", HTTPMethod: "DELETE", Path: path}
+	return s.client.do(ctx, op, nil, nil, opts...)
 }
 
 // ListProviderModels 获取供应商支持的模型列表
-func (s *ModelsService) ListProviderModels(ctx context.Context, req *ListProviderModelsRequest) (interface{}, error) {
+func (s *ModelsService) ListProviderModels(ctx context.Context, req *ListProviderModelsRequest, opts ...CallOption) (interface{}, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result interface{}
-	err := s.client.do(ctx, "POST", "/api/v1/models/provider/supported", req, &result)
+	op := &Operation{Service: "Models", Method: "ListProviderModels", HTTPMethod: "POST", Path: "/api/v1/models/provider/supported", Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -153,9 +283,14 @@ func (s *ModelsService) ListProviderModels(ctx context.Context, req *ListProvide
 }
 
 // Check 检查模型配置
-func (s *ModelsService) Check(ctx context.Context, req *CheckModelRequest) (*CheckModelResponse, error) {
+func (s *ModelsService) Check(ctx context.Context, req *CheckModelRequest, opts ...CallOption) (*CheckModelResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result CheckModelResponse
-	err := s.client.do(ctx, "POST", "/api/v1/models/check", req, &result)
+	op := &Operation{Service: "Models", Method: "Check", HTTPMethod: "POST", Path: "/api/v1/models/check", Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -164,9 +299,14 @@ func (s *ModelsService) Check(ctx context.Context, req *CheckModelRequest) (*Che
 
 // Upsert 根据 API Base 和 Model Name 创建或更新模型
 // 如果找到匹配的模型则更新，否则创建新模型
-func (s *ModelsService) Upsert(ctx context.Context, req *UpsertModelRequest) (*UpsertModelResponse, error) {
+func (s *ModelsService) Upsert(ctx context.Context, req *UpsertModelRequest, opts ...CallOption) (*UpsertModelResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	var result UpsertModelResponse
-	err := s.client.do(ctx, "POST", "/api/v1/models/upsert", req, &result)
+	op := &Operation{Service: "Models", Method: "Upsert", HTTPMethod: "POST", Path: "/api/v1/models/upsert", Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,125 @@
+package sdk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// errBodyNotReplayable 请求体已被读取且无法回退，不能再次发送
+var errBodyNotReplayable = errors.New("request body cannot be replayed")
+
+// multipartBody 流式 multipart 请求体
+//
+// 由已编码的字段和文件头（prefix）、文件内容、结束边界（suffix）三部分组成，
+// 发送时依次读取，内存占用与文件大小无关。
+// 文件实现 io.Seeker 时可以重放，用于重试和 http.Request.GetBody。
+//
+// 上一次尝试的请求体可能在 RoundTrip 返回后仍被 Transport 异步读取，
+// 因此文件同时实现 io.ReaderAt 时每次发送使用独立的 io.SectionReader，不移动文件的读取位置；
+// 只实现 io.Seeker 时等待上一次的请求体被关闭后再回退。
+type multipartBody struct {
+	prefix []byte
+	suffix []byte
+	file   io.Reader
+
+	// 文件的起始偏移，仅在 seekable 为 true 时有效
+	start    int64
+	seekable bool
+
+	// 文件同时实现 io.ReaderAt 时不为 nil
+	readerAt io.ReaderAt
+
+	// 文件剩余长度，-1 表示未知
+	size int64
+
+	opened bool
+
+	// 上一次返回的请求体关闭时被关闭，仅在通过 Seek 回退时使用
+	closed chan struct{}
+}
+
+// newMultipartBody 创建流式 multipart 请求体，prefix 必须以文件字段的头部结尾
+func newMultipartBody(prefix []byte, file io.Reader, boundary string) (*multipartBody, error) {
+	b := &multipartBody{
+		prefix: prefix,
+		suffix: []byte(fmt.Sprintf("\r\n--%s--\r\n", boundary)),
+		file:   file,
+		size:   -1,
+	}
+	if file == nil {
+		b.file = bytes.NewReader(nil)
+		b.size = 0
+		return b, nil
+	}
+
+	if seeker, ok := file.(io.Seeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err == nil {
+			end, err := seeker.Seek(0, io.SeekEnd)
+			if err != nil {
+				return nil, fmt.Errorf("failed to seek file: %w", err)
+			}
+			if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+				return nil, fmt.Errorf("failed to seek file: %w", err)
+			}
+			b.start = start
+			b.seekable = true
+			b.size = end - start
+			if readerAt, ok := file.(io.ReaderAt); ok {
+				b.readerAt = readerAt
+			}
+			return b, nil
+		}
+	}
+
+	// bytes.Buffer 等不可回退但长度已知的 Reader
+	if l, ok := file.(interface{ Len() int }); ok {
+		b.size = int64(l.Len())
+	}
+	return b, nil
+}
+
+// open 返回用于本次发送的请求体及其长度，长度未知时返回 -1（使用分块传输）
+func (b *multipartBody) open() (io.Reader, int64, error) {
+	file := b.file
+	switch {
+	case b.readerAt != nil:
+		file = io.NewSectionReader(b.readerAt, b.start, b.size)
+	case b.opened && !b.seekable:
+		return nil, 0, errBodyNotReplayable
+	case b.opened:
+		<-b.closed
+		if _, err := b.file.(io.Seeker).Seek(b.start, io.SeekStart); err != nil {
+			return nil, 0, fmt.Errorf("failed to rewind file: %w", err)
+		}
+	}
+	b.opened = true
+
+	contentLength := int64(-1)
+	if b.size >= 0 {
+		contentLength = int64(len(b.prefix)) + b.size + int64(len(b.suffix))
+	}
+
+	r := io.MultiReader(bytes.NewReader(b.prefix), file, bytes.NewReader(b.suffix))
+	if b.readerAt != nil || !b.seekable {
+		return r, contentLength, nil
+	}
+	b.closed = make(chan struct{})
+	return &notifyOnClose{Reader: r, closed: b.closed}, contentLength, nil
+}
+
+// notifyOnClose 在 Transport 关闭请求体时关闭 closed
+type notifyOnClose struct {
+	io.Reader
+	closed chan struct{}
+	once   sync.Once
+}
+
+// Close 实现 io.Closer
+func (n *notifyOnClose) Close() error {
+	n.once.Do(func() { close(n.closed) })
+	return nil
+}
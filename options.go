@@ -1,6 +1,7 @@
 package sdk
 
 import (
+	"log/slog"
 	"net/http"
 	"time"
 )
@@ -32,6 +33,119 @@ func WithTransport(transport http.RoundTripper) Option {
 // WithAPIKey 设置 API Key
 func WithAPIKey(apiKey string) Option {
 	return func(c *Client) {
-		c.apiKey = apiKey
+		c.credentials = nil
+		if apiKey != "" {
+			c.credentials = StaticCredentials(apiKey)
+		}
+	}
+}
+
+// WithCredentials 设置认证信息来源，每次请求前获取令牌；与 WithAPIKey 同时使用时以后设置的为准
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(c *Client) {
+		c.credentials = provider
+	}
+}
+
+// WithRetryPolicy 设置重试策略，传入 nil 则关闭重试
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithMiddleware 添加中间件，先添加的中间件在外层，多次调用会依次追加
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(c *Client) {
+		c.middlewares = append(c.middlewares, middlewares...)
+	}
+}
+
+// WithLogger 设置结构化日志，记录每次 HTTP 请求的方法、路径、状态码、耗时和请求 ID
+//
+// Authorization 请求头、模型配置中的 api_key 和 custom_headers 会被自动脱敏；
+// 请求体和响应体只在 logger 启用 Debug 级别时记录，并截断过长的内容。
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevel 设置成功请求的日志级别，默认为 Info；失败的请求总是以 Warn 级别记录
+func WithLogLevel(level slog.Level) Option {
+	return func(c *Client) {
+		c.logLevel = level
+	}
+}
+
+// WithValidation 设置是否在发送前校验请求参数，默认开启
+func WithValidation(enabled bool) Option {
+	return func(c *Client) {
+		c.skipValidation = !enabled
+	}
+}
+
+// WithRateLimit 限制每秒发起的请求数（包括重试），burst 为允许的突发请求数
+//
+// 不指定 classes 时限制所有操作；指定时每个分类各自计算，并与全局限制同时生效，
+// 例如 WithRateLimit(2, 1, ClassUpload) 只限制上传。rps 不大于 0 时取消对应的限制。
+func WithRateLimit(rps float64, burst int, classes ...OperationClass) Option {
+	return func(c *Client) {
+		for _, b := range c.budgets(classes) {
+			b.rate = nil
+			if rps > 0 {
+				b.rate = newRateLimiter(rps, burst)
+			}
+		}
+	}
+}
+
+// WithMaxInFlight 限制同时进行的请求数，流式请求在 Stream 关闭前一直占用名额
+//
+// classes 的含义与 WithRateLimit 相同。n 不大于 0 时取消对应的限制。
+func WithMaxInFlight(n int, classes ...OperationClass) Option {
+	return func(c *Client) {
+		for _, b := range c.budgets(classes) {
+			b.inFlight = nil
+			if n > 0 {
+				b.inFlight = make(chan struct{}, n)
+			}
+		}
+	}
+}
+
+// WithCircuitBreaker 开启熔断器，每个服务地址一个
+//
+// 连续失败达到阈值后熔断器打开，请求直接返回 ErrCircuitOpen 而不必等待超时；
+// 经过 OpenTimeout 后通过 Health.Check 探测服务是否恢复。
+func WithCircuitBreaker(config CircuitBreakerConfig) Option {
+	return func(c *Client) {
+		c.circuitConfig = &config
+	}
+}
+
+// WithEndpoints 添加服务地址，与 NewClient 的 baseURL 一起组成地址列表，baseURL 排在最前面
+//
+// 连续失败的地址会被暂时跳过；请求失败时自动切换到其他地址，
+// 非幂等请求（例如上传文档）只有在连接没有建立时才会切换。
+func WithEndpoints(baseURLs ...string) Option {
+	return func(c *Client) {
+		c.endpointURLs = append(c.endpointURLs, baseURLs...)
+	}
+}
+
+// WithEndpointStrategy 设置多个服务地址之间的选择策略，默认为 StrategyRoundRobin
+func WithEndpointStrategy(strategy EndpointStrategy) Option {
+	return func(c *Client) {
+		c.strategy = strategy
+	}
+}
+
+// WithHealthCheck 每隔 interval 请求所有地址的 /health，检查失败的地址暂时不参与选择
+//
+// 健康检查在后台 goroutine 中执行，不再使用客户端时需要调用 Close 停止。
+func WithHealthCheck(interval time.Duration) Option {
+	return func(c *Client) {
+		c.healthInterval = interval
 	}
 }
@@ -0,0 +1,24 @@
+module github.com/chaitin/raglite-go-sdk/otelraglite
+
+go 1.21
+
+require (
+	github.com/chaitin/raglite-go-sdk v0.0.0-00010101000000-000000000000
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/metric v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/sdk/metric v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+)
+
+// otelraglite 与 SDK 在同一仓库中开发，始终使用同一提交中的 SDK 构建和测试。
+// replace 只对本 module 生效，依赖 otelraglite 的 module 使用自己 go.mod 中的 SDK 版本，
+// 发布时需要先为 SDK 打标签，再将上面的 require 更新为该版本。
+replace github.com/chaitin/raglite-go-sdk => ../
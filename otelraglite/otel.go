@@ -0,0 +1,272 @@
+// Package otelraglite 为 RAGLite SDK 提供 OpenTelemetry 链路追踪和指标
+//
+// 为每次 SDK 操作创建一个 client span，记录数据集 ID、top_k、检索模式、结果数量和服务端耗时等属性，
+// 通过请求头传播 W3C Trace Context，并按操作记录耗时和错误指标：
+//
+//	client, err := sdk.NewClient(baseURL, otelraglite.WithTelemetry())
+//
+// 该包是独立的 Go module，核心 SDK 不依赖 OpenTelemetry。
+package otelraglite
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName 创建 Tracer 和 Meter 使用的 instrumentation scope 名称
+const ScopeName = "github.com/chaitin/raglite-go-sdk/otelraglite"
+
+// 属性名
+const (
+	AttrOperation       = attribute.Key("raglite.operation")
+	AttrDatasetID       = attribute.Key("raglite.dataset_id")
+	AttrTopK            = attribute.Key("raglite.top_k")
+	AttrRetrievalMode   = attribute.Key("raglite.retrieval_mode")
+	AttrThreshold       = attribute.Key("raglite.similarity_threshold")
+	AttrResultCount     = attribute.Key("raglite.result_count")
+	AttrServerLatencyMs = attribute.Key("raglite.server_latency_ms")
+	AttrStream          = attribute.Key("raglite.stream")
+	AttrQueueTimeMs     = attribute.Key("raglite.queue_time_ms")
+)
+
+// Option 配置选项
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+	propagators    propagation.TextMapPropagator
+}
+
+// WithTracerProvider 设置 TracerProvider，默认使用全局的 otel.GetTracerProvider()
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider 设置 MeterProvider，默认使用全局的 otel.GetMeterProvider()
+func WithMeterProvider(provider metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = provider
+	}
+}
+
+// WithPropagators 设置注入请求头的传播器，默认为 W3C Trace Context 和 Baggage
+func WithPropagators(propagators propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagators = propagators
+	}
+}
+
+// WithTelemetry 返回启用链路追踪和指标的客户端选项
+func WithTelemetry(opts ...Option) sdk.Option {
+	return sdk.WithMiddleware(NewMiddleware(opts...))
+}
+
+// NewMiddleware 创建 OpenTelemetry 中间件，需要与其他中间件组合时使用
+func NewMiddleware(opts ...Option) sdk.Middleware {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+		propagators:    propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	in := &instrumentation{
+		tracer:      cfg.tracerProvider.Tracer(ScopeName),
+		propagators: cfg.propagators,
+	}
+	in.initMetrics(cfg.meterProvider.Meter(ScopeName))
+	return in.middleware
+}
+
+type instrumentation struct {
+	tracer      trace.Tracer
+	propagators propagation.TextMapPropagator
+
+	duration      metric.Float64Histogram
+	errors        metric.Int64Counter
+	serverLatency metric.Float64Histogram
+	queueTime     metric.Float64Histogram
+}
+
+// initMetrics 创建指标，失败时 otel 返回可用的 no-op 实现并通过全局 ErrorHandler 报告
+func (in *instrumentation) initMetrics(meter metric.Meter) {
+	var err error
+	in.duration, err = meter.Float64Histogram("raglite.client.operation.duration",
+		metric.WithDescription("Duration of RAGLite SDK operations, including retries"),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	in.errors, err = meter.Int64Counter("raglite.client.operation.errors",
+		metric.WithDescription("Number of failed RAGLite SDK operations"),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	in.serverLatency, err = meter.Float64Histogram("raglite.server.search.latency",
+		metric.WithDescription("Search latency reported by the RAGLite server"),
+		metric.WithUnit("ms"))
+	if err != nil {
+		otel.Handle(err)
+	}
+	in.queueTime, err = meter.Float64Histogram("raglite.client.queue.duration",
+		metric.WithDescription("Time spent waiting for WithRateLimit and WithMaxInFlight budgets"),
+		metric.WithUnit("s"))
+	if err != nil {
+		otel.Handle(err)
+	}
+}
+
+func (in *instrumentation) middleware(next sdk.Invoker) sdk.Invoker {
+	return func(ctx context.Context, op *sdk.Operation) error {
+		opAttr := AttrOperation.String(op.FullMethod())
+		ctx, span := in.tracer.Start(ctx, "raglite "+op.FullMethod(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(requestAttributes(op)...))
+		defer span.End()
+
+		if op.Header == nil {
+			op.Header = http.Header{}
+		}
+		in.propagators.Inject(ctx, propagation.HeaderCarrier(op.Header))
+
+		start := time.Now()
+		err := next(ctx, op)
+		elapsed := time.Since(start).Seconds()
+
+		if op.QueueTime > 0 {
+			span.SetAttributes(AttrQueueTimeMs.Int64(op.QueueTime.Milliseconds()))
+		}
+		in.queueTime.Record(ctx, op.QueueTime.Seconds(), metric.WithAttributes(opAttr))
+
+		metricAttrs := []attribute.KeyValue{opAttr}
+		if err != nil {
+			errType := errorType(err)
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			span.SetAttributes(attribute.String("error.type", errType))
+			var apiErr *sdk.APIError
+			if errors.As(err, &apiErr) {
+				span.SetAttributes(attribute.Int("http.response.status_code", apiErr.StatusCode))
+			}
+			metricAttrs = append(metricAttrs, attribute.String("error.type", errType))
+			in.errors.Add(ctx, 1, metric.WithAttributes(metricAttrs...))
+		} else {
+			span.SetAttributes(resultAttributes(op)...)
+			if resp, ok := op.Result.(*sdk.SearchResponse); ok {
+				in.serverLatency.Record(ctx, float64(resp.LatencyMs), metric.WithAttributes(opAttr))
+			}
+		}
+		in.duration.Record(ctx, elapsed, metric.WithAttributes(metricAttrs...))
+		return err
+	}
+}
+
+// requestAttributes 从操作和请求中提取 span 属性
+func requestAttributes(op *sdk.Operation) []attribute.KeyValue {
+	path := op.Path
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	attrs := []attribute.KeyValue{
+		AttrOperation.String(op.FullMethod()),
+		attribute.String("rpc.system", "raglite"),
+		attribute.String("rpc.service", op.Service),
+		attribute.String("rpc.method", op.Method),
+		attribute.String("http.request.method", op.HTTPMethod),
+		attribute.String("url.path", path),
+	}
+	if op.DatasetID != "" {
+		attrs = append(attrs, AttrDatasetID.String(op.DatasetID))
+	}
+	if op.Stream {
+		attrs = append(attrs, AttrStream.Bool(true))
+	}
+
+	switch req := op.Request.(type) {
+	case *sdk.RetrieveRequest:
+		attrs = append(attrs, retrievalAttributes(req.TopK, req.RetrievalMode, req.SimilarityThreshold)...)
+	case *sdk.QARequest:
+		attrs = append(attrs, retrievalAttributes(req.TopK, req.RetrievalMode, req.SimilarityThreshold)...)
+	}
+	return attrs
+}
+
+func retrievalAttributes(topK int, mode string, threshold float64) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	if topK > 0 {
+		attrs = append(attrs, AttrTopK.Int(topK))
+	}
+	if mode != "" {
+		attrs = append(attrs, AttrRetrievalMode.String(mode))
+	}
+	if threshold > 0 {
+		attrs = append(attrs, AttrThreshold.Float64(threshold))
+	}
+	return attrs
+}
+
+// resultAttributes 从解码后的结果中提取 span 属性
+func resultAttributes(op *sdk.Operation) []attribute.KeyValue {
+	switch result := op.Result.(type) {
+	case *sdk.SearchResponse:
+		return []attribute.KeyValue{
+			AttrResultCount.Int(len(result.Results)),
+			AttrServerLatencyMs.Int64(result.LatencyMs),
+		}
+	case *sdk.QAResponse:
+		return []attribute.KeyValue{AttrResultCount.Int(len(result.Context))}
+	case *sdk.ListDocumentsResponse:
+		return []attribute.KeyValue{AttrResultCount.Int(len(result.Documents))}
+	case *sdk.ListDatasetsResponse:
+		return []attribute.KeyValue{AttrResultCount.Int(len(result.Datasets))}
+	case *sdk.ListModelsResponse:
+		return []attribute.KeyValue{AttrResultCount.Int(len(result.Models))}
+	}
+	return nil
+}
+
+// errorType 返回低基数的错误分类，用作指标属性
+func errorType(err error) string {
+	var (
+		apiErr        *sdk.APIError
+		validationErr *sdk.ValidationError
+		streamErr     *sdk.StreamError
+		transportErr  *sdk.TransportError
+	)
+	switch {
+	case errors.As(err, &apiErr):
+		return strconv.Itoa(apiErr.StatusCode)
+	case errors.Is(err, sdk.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.As(err, &validationErr):
+		return "validation"
+	case errors.As(err, &streamErr):
+		return "stream"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.As(err, &transportErr):
+		return "transport"
+	default:
+		return "other"
+	}
+}
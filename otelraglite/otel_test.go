@@ -0,0 +1,200 @@
+package otelraglite
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+	"github.com/chaitin/raglite-go-sdk/sdktest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// telemetry 记录 span 和指标的内存 provider
+type telemetry struct {
+	spans  *tracetest.SpanRecorder
+	reader *sdkmetric.ManualReader
+}
+
+func newTelemetryClient(t *testing.T, srv *sdktest.Server) (*sdk.Client, *telemetry) {
+	t.Helper()
+	tel := &telemetry{spans: tracetest.NewSpanRecorder(), reader: sdkmetric.NewManualReader()}
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(tel.spans))
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(tel.reader))
+	t.Cleanup(func() {
+		tp.Shutdown(context.Background())
+		mp.Shutdown(context.Background())
+	})
+
+	client := srv.Client(
+		sdk.WithRetryPolicy(nil),
+		WithTelemetry(WithTracerProvider(tp), WithMeterProvider(mp)),
+	)
+	t.Cleanup(func() { client.Close() })
+	return client, tel
+}
+
+// metric 按名称查找指标
+func (tel *telemetry) metric(t *testing.T, name string) metricdata.Aggregation {
+	t.Helper()
+	var rm metricdata.ResourceMetrics
+	if err := tel.reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		if sm.Scope.Name != ScopeName {
+			continue
+		}
+		for _, m := range sm.Metrics {
+			if m.Name == name {
+				return m.Data
+			}
+		}
+	}
+	t.Fatalf("metric %s not recorded", name)
+	return nil
+}
+
+// attrsOf 将属性转换为 map，便于比较
+func attrsOf(kvs []attribute.KeyValue) map[attribute.Key]string {
+	m := make(map[attribute.Key]string, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value.Emit()
+	}
+	return m
+}
+
+func TestSearchSpanAndMetrics(t *testing.T) {
+	srv := sdktest.NewServer()
+	defer srv.Close()
+	srv.AddDataset(sdk.Dataset{ID: "ds-1", Name: "docs"})
+	srv.ScriptSearch("raglite", sdk.SearchResult{ChunkID: "c1"}, sdk.SearchResult{ChunkID: "c2"})
+	client, tel := newTelemetryClient(t, srv)
+
+	_, err := client.Search.Retrieve(context.Background(), &sdk.RetrieveRequest{
+		DatasetID:     "ds-1",
+		Query:         "raglite",
+		TopK:          5,
+		RetrievalMode: "smart",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := tel.spans.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "raglite Search.Retrieve" {
+		t.Errorf("span name = %q, want raglite Search.Retrieve", span.Name())
+	}
+	if span.Status().Code != codes.Unset {
+		t.Errorf("span status = %v, want unset", span.Status())
+	}
+	attrs := attrsOf(span.Attributes())
+	want := map[attribute.Key]string{
+		AttrOperation:                        "Search.Retrieve",
+		AttrDatasetID:                        "ds-1",
+		AttrTopK:                             "5",
+		AttrRetrievalMode:                    "smart",
+		AttrResultCount:                      "2",
+		attribute.Key("rpc.system"):          "raglite",
+		attribute.Key("url.path"):            "/api/v1/search",
+		attribute.Key("http.request.method"): "POST",
+	}
+	for k, v := range want {
+		if attrs[k] != v {
+			t.Errorf("attribute %s = %q, want %q", k, attrs[k], v)
+		}
+	}
+	if _, ok := attrs[AttrServerLatencyMs]; !ok {
+		t.Errorf("attribute %s missing", AttrServerLatencyMs)
+	}
+
+	// 请求头中注入了当前 span 的 traceparent
+	reqs := srv.Requests()
+	wantParent := fmt.Sprintf("00-%s-%s-01", span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	if got := reqs[len(reqs)-1].Header.Get("traceparent"); got != wantParent {
+		t.Errorf("traceparent = %q, want %q", got, wantParent)
+	}
+
+	duration := tel.metric(t, "raglite.client.operation.duration").(metricdata.Histogram[float64])
+	if len(duration.DataPoints) != 1 || duration.DataPoints[0].Count != 1 {
+		t.Fatalf("duration data points = %+v, want one observation", duration.DataPoints)
+	}
+	if op, _ := duration.DataPoints[0].Attributes.Value(AttrOperation); op.AsString() != "Search.Retrieve" {
+		t.Errorf("duration operation = %q, want Search.Retrieve", op.AsString())
+	}
+	latency := tel.metric(t, "raglite.server.search.latency").(metricdata.Histogram[float64])
+	if len(latency.DataPoints) != 1 || latency.DataPoints[0].Count != 1 {
+		t.Errorf("server latency data points = %+v, want one observation", latency.DataPoints)
+	}
+}
+
+func TestErrorSpanAndMetrics(t *testing.T) {
+	srv := sdktest.NewServer()
+	defer srv.Close()
+	srv.InjectFault(sdktest.Fault{Path: "/api/v1/datasets/*", StatusCode: 503})
+	client, tel := newTelemetryClient(t, srv)
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Datasets.Get(context.Background(), "ds-1"); err == nil {
+			t.Fatal("Get succeeded, want the injected 503")
+		}
+	}
+
+	spans := tel.spans.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want error", span.Status())
+	}
+	attrs := attrsOf(span.Attributes())
+	if attrs["error.type"] != "503" || attrs["http.response.status_code"] != "503" {
+		t.Errorf("error attributes = %q / %q, want 503", attrs["error.type"], attrs["http.response.status_code"])
+	}
+	if events := span.Events(); len(events) != 1 || events[0].Name != "exception" {
+		t.Errorf("span events = %+v, want one exception event", events)
+	}
+
+	errorsMetric := tel.metric(t, "raglite.client.operation.errors").(metricdata.Sum[int64])
+	if len(errorsMetric.DataPoints) != 1 {
+		t.Fatalf("error data points = %+v, want one series", errorsMetric.DataPoints)
+	}
+	dp := errorsMetric.DataPoints[0]
+	errType, _ := dp.Attributes.Value("error.type")
+	if dp.Value != 2 || errType.AsString() != "503" {
+		t.Errorf("errors = %d with error.type %q, want 2 with 503", dp.Value, errType.AsString())
+	}
+}
+
+func TestErrorType(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&sdk.APIError{StatusCode: 404}, "404"},
+		{fmt.Errorf("wrapped: %w", &sdk.APIError{StatusCode: 429}), "429"},
+		{&sdk.CircuitOpenError{BaseURL: "http://raglite.test"}, "circuit_open"},
+		{&sdk.ValidationError{}, "validation"},
+		{&sdk.StreamError{}, "stream"},
+		{context.Canceled, "canceled"},
+		{&sdk.TransportError{Err: context.DeadlineExceeded}, "deadline_exceeded"},
+		{&sdk.TransportError{Err: errors.New("connection refused")}, "transport"},
+		{errors.New("boom"), "other"},
+	}
+	for _, tt := range tests {
+		if got := errorType(tt.err); got != tt.want {
+			t.Errorf("errorType(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
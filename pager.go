@@ -0,0 +1,154 @@
+package sdk
+
+import (
+	"context"
+	"strconv"
+)
+
+// PageSizeAll 作为 PageSize 时表示不分页、一次返回全部结果，数据量大时慎用
+//
+// 旧版本中 ListDocumentsRequest.PageSize 为 0 即表示不分页，现在 0 表示使用服务端默认值，
+// 依赖旧行为的代码需要改用 PageSizeAll。
+const PageSizeAll = -1
+
+// DefaultPagerPageSize 分页迭代时默认的每页数量
+const DefaultPagerPageSize = 50
+
+// PagerOptions 分页迭代选项
+type PagerOptions struct {
+	// 每页数量，默认 DefaultPagerPageSize
+	PageSize int
+
+	// 最多返回的条目数，0 表示不限制
+	MaxItems int
+}
+
+// PageFetcher 获取指定页的数据及总数
+type PageFetcher[T any] func(ctx context.Context, page, pageSize int) ([]T, int64, error)
+
+// Pager 分页迭代器，按需逐页请求，用法与 Stream 类似：
+//
+//	pager := client.Documents.ListPager(&sdk.ListDocumentsRequest{DatasetID: datasetID}, nil)
+//	for pager.Next(ctx) {
+//		doc := pager.Current()
+//		// ...
+//	}
+//	if err := pager.Err(); err != nil {
+//		return err
+//	}
+//
+// Pager 不是并发安全的。
+type Pager[T any] struct {
+	fetch    PageFetcher[T]
+	pageSize int
+	maxItems int
+
+	page    int
+	items   []T
+	current T
+	count   int
+	total   int64
+	err     error
+	done    bool
+}
+
+// NewPager 创建分页迭代器，用于对自定义的分页接口复用 Pager，或在测试中构造 Pager
+func NewPager[T any](fetch PageFetcher[T], opts *PagerOptions) *Pager[T] {
+	p := &Pager[T]{
+		fetch:    fetch,
+		pageSize: DefaultPagerPageSize,
+	}
+	if opts != nil {
+		if opts.PageSize > 0 {
+			p.pageSize = opts.PageSize
+		}
+		p.maxItems = opts.MaxItems
+	}
+	return p
+}
+
+// Next 移动到下一个条目，需要时请求下一页
+// 全部读取完毕、达到 MaxItems、出错或 ctx 结束时返回 false
+func (p *Pager[T]) Next(ctx context.Context) bool {
+	if p.maxItems > 0 && p.count >= p.maxItems {
+		p.done = true
+		p.items = nil
+		return false
+	}
+
+	for len(p.items) == 0 {
+		if p.done {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			p.err = err
+			p.done = true
+			return false
+		}
+
+		p.page++
+		items, total, err := p.fetch(ctx, p.page, p.pageSize)
+		if err != nil {
+			p.err = err
+			p.done = true
+			return false
+		}
+		p.total = total
+		p.items = items
+
+		// 服务端可能将每页数量限制在请求值以下，返回了总数时只以总数判断是否结束；
+		// 没有返回总数时，数量不足一页（或服务端忽略了分页参数）视为最后一页
+		fetched := p.count + len(items)
+		switch {
+		case len(items) == 0:
+			p.done = true
+		case total > 0:
+			p.done = int64(fetched) >= total
+		default:
+			p.done = len(items) != p.pageSize
+		}
+	}
+
+	p.current = p.items[0]
+	p.items = p.items[1:]
+	p.count++
+	return true
+}
+
+// Current 返回当前条目
+func (p *Pager[T]) Current() T {
+	return p.current
+}
+
+// Err 返回迭代过程中遇到的错误
+func (p *Pager[T]) Err() error {
+	return p.err
+}
+
+// Total 返回服务端报告的总数，在第一次调用 Next 之后有效
+func (p *Pager[T]) Total() int64 {
+	return p.total
+}
+
+// All 读取剩余的全部条目
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for p.Next(ctx) {
+		items = append(items, p.Current())
+	}
+	return items, p.Err()
+}
+
+// setPageParams 设置分页查询参数
+// pageSize 为 0 时使用服务端默认值，为 PageSizeAll 时不分页
+func setPageParams(params map[string]string, page, pageSize int) {
+	if page > 0 {
+		params["page"] = strconv.Itoa(page)
+	}
+	switch {
+	case pageSize > 0:
+		params["page_size"] = strconv.Itoa(pageSize)
+	case pageSize < 0:
+		params["page_size"] = "0"
+	}
+}
@@ -0,0 +1,189 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// fakePages 按页返回 pages 中的数据，并记录请求过的页码
+type fakePages struct {
+	pages   [][]int
+	total   int64
+	errPage int
+	fetched []int
+}
+
+func (f *fakePages) fetch(ctx context.Context, page, pageSize int) ([]int, int64, error) {
+	f.fetched = append(f.fetched, page)
+	if page == f.errPage {
+		return nil, 0, errors.New("boom")
+	}
+	if page > len(f.pages) {
+		return nil, f.total, nil
+	}
+	return f.pages[page-1], f.total, nil
+}
+
+func TestPagerTermination(t *testing.T) {
+	tests := []struct {
+		name        string
+		pages       [][]int
+		total       int64
+		opts        *PagerOptions
+		want        []int
+		wantFetched []int
+	}{
+		{
+			name:        "total reached on a full page",
+			pages:       [][]int{{1, 2, 3}, {4, 5, 6}},
+			total:       6,
+			opts:        &PagerOptions{PageSize: 3},
+			want:        []int{1, 2, 3, 4, 5, 6},
+			wantFetched: []int{1, 2},
+		},
+		{
+			// 服务端把每页数量限制为 2，短页不代表结束
+			name:        "total drives past server-capped short pages",
+			pages:       [][]int{{1, 2}, {3, 4}, {5}},
+			total:       5,
+			opts:        &PagerOptions{PageSize: 3},
+			want:        []int{1, 2, 3, 4, 5},
+			wantFetched: []int{1, 2, 3},
+		},
+		{
+			name:        "empty page ends an overstated total",
+			pages:       [][]int{{1, 2}},
+			total:       10,
+			opts:        &PagerOptions{PageSize: 2},
+			want:        []int{1, 2},
+			wantFetched: []int{1, 2},
+		},
+		{
+			name:        "short page ends without a total",
+			pages:       [][]int{{1, 2, 3}, {4, 5, 6}, {7}},
+			opts:        &PagerOptions{PageSize: 3},
+			want:        []int{1, 2, 3, 4, 5, 6, 7},
+			wantFetched: []int{1, 2, 3},
+		},
+		{
+			name:        "empty page ends an exact multiple without a total",
+			pages:       [][]int{{1, 2}, {3, 4}},
+			opts:        &PagerOptions{PageSize: 2},
+			want:        []int{1, 2, 3, 4},
+			wantFetched: []int{1, 2, 3},
+		},
+		{
+			name:        "oversized page ends without a total",
+			pages:       [][]int{{1, 2, 3, 4}},
+			opts:        &PagerOptions{PageSize: 2},
+			want:        []int{1, 2, 3, 4},
+			wantFetched: []int{1},
+		},
+		{
+			name:        "empty first page",
+			pages:       nil,
+			want:        nil,
+			wantFetched: []int{1},
+		},
+		{
+			name:        "max items stops before the next page",
+			pages:       [][]int{{1, 2}, {3, 4}},
+			total:       4,
+			opts:        &PagerOptions{PageSize: 2, MaxItems: 2},
+			want:        []int{1, 2},
+			wantFetched: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakePages{pages: tt.pages, total: tt.total}
+			pager := NewPager(f.fetch, tt.opts)
+			got, err := pager.All(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("items = %v, want %v", got, tt.want)
+			}
+			if !reflect.DeepEqual(f.fetched, tt.wantFetched) {
+				t.Errorf("fetched pages = %v, want %v", f.fetched, tt.wantFetched)
+			}
+			if pager.Next(context.Background()) {
+				t.Error("Next() = true after the pager finished")
+			}
+		})
+	}
+}
+
+func TestPagerError(t *testing.T) {
+	f := &fakePages{pages: [][]int{{1, 2}, {3, 4}}, total: 4, errPage: 2}
+	pager := NewPager(f.fetch, &PagerOptions{PageSize: 2})
+
+	got, err := pager.All(context.Background())
+	if err == nil || err.Error() != "boom" {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if !reflect.DeepEqual(got, []int{1, 2}) || pager.Total() != 4 {
+		t.Errorf("items = %v total = %d, want the first page and total 4", got, pager.Total())
+	}
+	if pager.Next(context.Background()) || !reflect.DeepEqual(f.fetched, []int{1, 2}) {
+		t.Errorf("fetched pages = %v, want no request after the error", f.fetched)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	pager = NewPager(f.fetch, nil)
+	if pager.Next(ctx) || !errors.Is(pager.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", pager.Err())
+	}
+}
+
+func TestSetPageParams(t *testing.T) {
+	tests := []struct {
+		page, pageSize int
+		want           map[string]string
+	}{
+		{0, 0, map[string]string{}},
+		{2, 0, map[string]string{"page": "2"}},
+		{1, 50, map[string]string{"page": "1", "page_size": "50"}},
+		{0, PageSizeAll, map[string]string{"page_size": "0"}},
+	}
+	for _, tt := range tests {
+		params := map[string]string{}
+		setPageParams(params, tt.page, tt.pageSize)
+		if !reflect.DeepEqual(params, tt.want) {
+			t.Errorf("setPageParams(%d, %d) = %v, want %v", tt.page, tt.pageSize, params, tt.want)
+		}
+	}
+}
+
+func TestListPagerRequestsPages(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		data := `{"documents":[{"id":"d1"},{"id":"d2"}],"total":3}`
+		if r.URL.Query().Get("page") == "2" {
+			data = `{"documents":[{"id":"d3"}],"total":3}`
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"data":` + data + `}`))
+	}))
+	t.Cleanup(srv.Close)
+	client := newTestClient(t, srv.URL)
+
+	docs, err := client.Documents.ListPager(&ListDocumentsRequest{DatasetID: "ds-1", Page: 5}, &PagerOptions{PageSize: 2}).All(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 3 || docs[2].ID != "d3" {
+		t.Errorf("documents = %+v, want d1..d3", docs)
+	}
+	want := []string{"page=1&page_size=2", "page=2&page_size=2"}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("queries = %q, want %q", queries, want)
+	}
+}
@@ -1,6 +1,9 @@
 package sdk
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // QAService 问答服务
 type QAService struct {
@@ -9,12 +12,25 @@ type QAService struct {
 
 // QARequest 问答请求
 type QARequest struct {
-	Query               string  `json:"query"`
-	DatasetID           string  `json:"dataset_id"`
-	TopK                int     `json:"top_k,omitempty"`
-	RetrievalMode       string  `json:"retrieval_mode,omitempty"` // full | smart
-	Stream              bool    `json:"stream,omitempty"`
-	SimilarityThreshold float64 `json:"similarity_threshold,omitempty"`
+	Query               string        `json:"query"`
+	DatasetID           string        `json:"dataset_id"`
+	TopK                int           `json:"top_k,omitempty"`
+	RetrievalMode       string        `json:"retrieval_mode,omitempty"` // full | smart
+	Stream              bool          `json:"stream,omitempty"`
+	SimilarityThreshold float64       `json:"similarity_threshold,omitempty"`
+	ChatHistory         []ChatMessage `json:"chat_history,omitempty"`
+}
+
+// Validate 校验请求参数
+func (r *QARequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("query", r.Query)
+	v.required("dataset_id", r.DatasetID)
+	v.retrieval(r.TopK, r.RetrievalMode, r.SimilarityThreshold, r.ChatHistory)
+	return v.err()
 }
 
 // QAResponse 问答响应
@@ -23,17 +39,133 @@ type QAResponse struct {
 	Context []SearchResult `json:"context"`
 }
 
+// 流式问答事件类型
+const (
+	QAEventAnswer  = "answer"  // 答案增量，见 Delta
+	QAEventContext = "context" // 召回的上下文，见 Context
+	QAEventDone    = "done"    // 结束汇总，见 Answer 和 Context
+	QAEventError   = "error"   // 服务端错误，见 Error
+)
+
+// QAStreamEvent 流式问答事件
+type QAStreamEvent struct {
+	Type    string
+	Delta   string
+	Context []SearchResult
+	Answer  string
+	Error   string
+}
+
+// QAStream 流式问答结果
+type QAStream = Stream[QAStreamEvent]
+
 // Ask 提出问题并获取答案
-func (s *QAService) Ask(ctx context.Context, req *QARequest) (*QAResponse, error) {
+// req.Stream 为 true 时以流式方式请求，并在读取完整个流后返回汇总结果
+func (s *QAService) Ask(ctx context.Context, req *QARequest, opts ...CallOption) (*QAResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	// 设置默认值
 	if req.TopK <= 0 {
 		req.TopK = 10
 	}
 
+	if req.Stream {
+		return s.askStreamed(ctx, req, opts...)
+	}
+
 	var result QAResponse
-	err := s.client.do(ctx, "POST", "/api/v1/qa", req, &result)
+	op := &Operation{Service: "QA", Method: "Ask", HTTPMethod: "POST", Path: "/api/v1/qa", DatasetID: req.DatasetID, Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
+
+// AskStream 以流式方式提出问题，答案逐段返回
+// 服务端可以返回 SSE（text/event-stream）或 NDJSON 格式，取消 ctx 会中断读取
+func (s *QAService) AskStream(ctx context.Context, req *QARequest, opts ...CallOption) (*QAStream, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
+	// 设置默认值
+	if req.TopK <= 0 {
+		req.TopK = 10
+	}
+
+	body := *req
+	body.Stream = true
+
+	op := &Operation{Service: "QA", Method: "AskStream", HTTPMethod: "POST", Path: "/api/v1/qa", DatasetID: req.DatasetID, Request: &body}
+	resp, err := s.client.openStream(ctx, op, &body, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newStream[QAStreamEvent](ctx, resp, newTextStreamDecoder[QAStreamEvent](&qaStreamEvents{})), nil
+}
+
+// askStreamed 读取整个流并汇总为 QAResponse
+func (s *QAService) askStreamed(ctx context.Context, req *QARequest, opts ...CallOption) (*QAResponse, error) {
+	stream, err := s.AskStream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var result QAResponse
+	for stream.Next() {
+		if ev := stream.Current(); ev.Type == QAEventDone {
+			result.Answer = ev.Answer
+			result.Context = ev.Context
+		}
+	}
+	if err := stream.Err(); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// qaStreamEvents 将文本流事件映射为 QAStreamEvent，并收集检索结果用于结束汇总
+type qaStreamEvents struct {
+	context []SearchResult
+}
+
+func (e *qaStreamEvents) extra(typ string, done bool, data []byte) ([]QAStreamEvent, bool) {
+	var p struct {
+		Context []SearchResult `json:"context"`
+	}
+	_ = json.Unmarshal(data, &p)
+
+	switch {
+	case typ == QAEventContext || typ == "sources" || typ == "references":
+		e.context = append(e.context, p.Context...)
+		return []QAStreamEvent{{Type: QAEventContext, Context: p.Context}}, true
+	case done && len(p.Context) > 0:
+		e.context = p.Context
+	}
+	return nil, false
+}
+
+func (e *qaStreamEvents) response(data []byte) (string, []QAStreamEvent, error) {
+	var resp QAResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return "", nil, err
+	}
+	e.context = append(e.context, resp.Context...)
+	return resp.Answer, []QAStreamEvent{{Type: QAEventContext, Context: resp.Context}}, nil
+}
+
+func (e *qaStreamEvents) deltaEvent(delta string) QAStreamEvent {
+	return QAStreamEvent{Type: QAEventAnswer, Delta: delta}
+}
+
+func (e *qaStreamEvents) doneEvent(answer string) QAStreamEvent {
+	return QAStreamEvent{Type: QAEventDone, Answer: answer, Context: e.context}
+}
+
+func (e *qaStreamEvents) errorEvent(msg string) QAStreamEvent {
+	return QAStreamEvent{Type: QAEventError, Error: msg}
+}
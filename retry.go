@@ -0,0 +1,153 @@
+package sdk
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy 重试策略
+//
+// 幂等方法（GET/HEAD/PUT/DELETE/OPTIONS）和设置了 WithIdempotencyKey 的请求
+// 在连接失败或返回 RetryableStatuses 中的状态码时重试；
+// 其他非幂等请求仅在服务端返回 429 时重试，此时请求尚未被处理。
+type RetryPolicy struct {
+	// 最大重试次数（不含首次请求），0 表示不重试
+	MaxRetries int
+
+	// 首次重试前的等待时间
+	InitialBackoff time.Duration
+
+	// 单次等待时间上限
+	MaxBackoff time.Duration
+
+	// 服务端 Retry-After 指定的等待时间上限，超过时按上限等待；0 表示使用 MaxBackoff
+	MaxRetryAfter time.Duration
+
+	// 每次重试等待时间的增长倍数
+	Multiplier float64
+
+	// 随机抖动比例，取值 [0, 1]，实际等待时间在 [d*(1-Jitter), d] 之间
+	Jitter float64
+
+	// 需要重试的 HTTP 状态码
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy 返回默认重试策略
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		MaxRetryAfter:  30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.5,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// backoff 计算第 attempt 次重试（从 0 开始）前的等待时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+
+	if p.Jitter > 0 {
+		jitter := math.Min(p.Jitter, 1)
+		d -= d * jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// wait 计算第 attempt 次重试前的等待时间：取退避时间和 Retry-After 中较大者，
+// Retry-After 不超过 MaxRetryAfter
+func (p *RetryPolicy) wait(attempt int, retryAfter time.Duration) time.Duration {
+	limit := p.MaxRetryAfter
+	if limit <= 0 {
+		limit = p.MaxBackoff
+	}
+	if limit > 0 && retryAfter > limit {
+		retryAfter = limit
+	}
+
+	if d := p.backoff(attempt); d > retryAfter {
+		return d
+	}
+	return retryAfter
+}
+
+// retryableStatus 状态码是否需要重试，idempotent 表示请求可以安全地重发
+func (p *RetryPolicy) retryableStatus(idempotent bool, statusCode int) bool {
+	if statusCode != http.StatusTooManyRequests && !idempotent {
+		return false
+	}
+	for _, s := range p.RetryableStatuses {
+		if s == statusCode {
+			return true
+		}
+	}
+	return false
+}
+
+// isIdempotent 方法是否幂等
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter 解析 Retry-After 响应头，支持秒数和 HTTP 日期两种格式
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+// sleep 等待指定时间，如果 context 先结束或等待会超出截止时间则返回 false
+func sleep(ctx context.Context, d time.Duration) bool {
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < d {
+		return false
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
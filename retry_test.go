@@ -0,0 +1,168 @@
+package sdk
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := &RetryPolicy{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 100 * time.Millisecond},
+		{1, 200 * time.Millisecond},
+		{2, 400 * time.Millisecond},
+		{3, 800 * time.Millisecond},
+		{4, time.Second},
+		{10, time.Second},
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffMultiplierBelowOne(t *testing.T) {
+	policy := &RetryPolicy{InitialBackoff: 100 * time.Millisecond, Multiplier: 0.5}
+	for attempt := 0; attempt < 3; attempt++ {
+		if got := policy.backoff(attempt); got != 100*time.Millisecond {
+			t.Errorf("backoff(%d) = %s, want constant 100ms", attempt, got)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	tests := []struct {
+		name   string
+		jitter float64
+		min    time.Duration
+		max    time.Duration
+	}{
+		{"half", 0.5, 500 * time.Millisecond, time.Second},
+		{"full", 1, 0, time.Second},
+		{"clamped above one", 3, 0, time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &RetryPolicy{InitialBackoff: time.Second, Multiplier: 2, Jitter: tt.jitter}
+			seen := make(map[time.Duration]bool)
+			for i := 0; i < 200; i++ {
+				d := policy.backoff(0)
+				if d < tt.min || d > tt.max {
+					t.Fatalf("backoff(0) = %s, want within [%s, %s]", d, tt.min, tt.max)
+				}
+				seen[d] = true
+			}
+			if len(seen) < 2 {
+				t.Errorf("backoff(0) returned the same value %d times, want jitter", 200)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxRetryAfter time.Duration
+		maxBackoff    time.Duration
+		retryAfter    time.Duration
+		want          time.Duration
+	}{
+		{"no Retry-After uses the backoff", time.Minute, time.Second, 0, 100 * time.Millisecond},
+		{"shorter Retry-After keeps the backoff", time.Minute, time.Second, 50 * time.Millisecond, 100 * time.Millisecond},
+		{"longer Retry-After is honored", time.Minute, time.Second, 20 * time.Second, 20 * time.Second},
+		{"Retry-After is capped at MaxRetryAfter", time.Minute, time.Second, time.Hour, time.Minute},
+		{"MaxRetryAfter defaults to MaxBackoff", 0, time.Second, time.Hour, time.Second},
+		{"no limits", 0, 0, time.Hour, time.Hour},
+	}
+	for _, tt := range tests {
+		policy := &RetryPolicy{
+			InitialBackoff: 100 * time.Millisecond,
+			MaxBackoff:     tt.maxBackoff,
+			MaxRetryAfter:  tt.maxRetryAfter,
+			Multiplier:     2,
+		}
+		if got := policy.wait(0, tt.retryAfter); got != tt.want {
+			t.Errorf("%s: wait(0, %s) = %s, want %s", tt.name, tt.retryAfter, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyRetryableStatus(t *testing.T) {
+	policy := DefaultRetryPolicy()
+
+	tests := []struct {
+		name       string
+		idempotent bool
+		status     int
+		want       bool
+	}{
+		{"idempotent 503", true, http.StatusServiceUnavailable, true},
+		{"idempotent 429", true, http.StatusTooManyRequests, true},
+		{"idempotent 404", true, http.StatusNotFound, false},
+		{"idempotent 501 not listed", true, http.StatusNotImplemented, false},
+		{"non-idempotent 429", false, http.StatusTooManyRequests, true},
+		{"non-idempotent 500", false, http.StatusInternalServerError, false},
+		{"non-idempotent 503", false, http.StatusServiceUnavailable, false},
+	}
+	for _, tt := range tests {
+		if got := policy.retryableStatus(tt.idempotent, tt.status); got != tt.want {
+			t.Errorf("%s: retryableStatus(%v, %d) = %v, want %v", tt.name, tt.idempotent, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := map[string]bool{
+		http.MethodGet:     true,
+		http.MethodHead:    true,
+		http.MethodPut:     true,
+		http.MethodDelete:  true,
+		http.MethodOptions: true,
+		http.MethodPost:    false,
+		http.MethodPatch:   false,
+	}
+	for method, want := range tests {
+		if got := isIdempotent(method); got != want {
+			t.Errorf("isIdempotent(%s) = %v, want %v", method, got, want)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"empty", "", 0, false},
+		{"seconds", "3", 3 * time.Second, true},
+		{"zero", "0", 0, true},
+		{"negative", "-1", 0, false},
+		{"invalid", "soon", 0, false},
+		{"past date", time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: parseRetryAfter(%q) = %s, %v, want %s, %v", tt.name, tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	// HTTP 日期只精确到秒
+	value := time.Now().Add(time.Minute).UTC().Format(http.TimeFormat)
+	got, ok := parseRetryAfter(value)
+	if !ok || got < 58*time.Second || got > time.Minute {
+		t.Errorf("parseRetryAfter(%q) = %s, %v, want about 1m", value, got, ok)
+	}
+}
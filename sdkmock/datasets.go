@@ -0,0 +1,91 @@
+package sdkmock
+
+import (
+	"context"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// Datasets sdk.DatasetsAPI 的 mock，未设置 ListPagerFunc 时 ListPager 基于 List 分页
+type Datasets struct {
+	CreateFunc    func(ctx context.Context, req *sdk.CreateDatasetRequest) (*sdk.Dataset, error)
+	ListFunc      func(ctx context.Context, req *sdk.ListDatasetsRequest) (*sdk.ListDatasetsResponse, error)
+	ListPagerFunc func(req *sdk.ListDatasetsRequest, opts *sdk.PagerOptions) *sdk.Pager[sdk.Dataset]
+	GetFunc       func(ctx context.Context, datasetID string) (*sdk.Dataset, error)
+	UpdateFunc    func(ctx context.Context, datasetID string, req *sdk.UpdateDatasetRequest) (*sdk.Dataset, error)
+	DeleteFunc    func(ctx context.Context, datasetID string) error
+	GetStatsFunc  func(ctx context.Context, datasetID string) (*sdk.DatasetStats, error)
+
+	recorder
+}
+
+var _ sdk.DatasetsAPI = (*Datasets)(nil)
+
+func (m *Datasets) Create(ctx context.Context, req *sdk.CreateDatasetRequest, opts ...sdk.CallOption) (_ *sdk.Dataset, err error) {
+	defer m.record("Create", opts, req)(&err)
+	if m.CreateFunc == nil {
+		return nil, notMocked("Datasets.Create")
+	}
+	return m.CreateFunc(ctx, req)
+}
+
+func (m *Datasets) List(ctx context.Context, req *sdk.ListDatasetsRequest, opts ...sdk.CallOption) (_ *sdk.ListDatasetsResponse, err error) {
+	defer m.record("List", opts, req)(&err)
+	if m.ListFunc == nil {
+		return nil, notMocked("Datasets.List")
+	}
+	return m.ListFunc(ctx, req)
+}
+
+func (m *Datasets) ListPager(req *sdk.ListDatasetsRequest, opts *sdk.PagerOptions, callOpts ...sdk.CallOption) *sdk.Pager[sdk.Dataset] {
+	m.record("ListPager", callOpts, req, opts)
+	if m.ListPagerFunc != nil {
+		return m.ListPagerFunc(req, opts)
+	}
+	return sdk.NewPager(func(ctx context.Context, page, pageSize int) ([]sdk.Dataset, int64, error) {
+		var pageReq sdk.ListDatasetsRequest
+		if req != nil {
+			pageReq = *req
+		}
+		pageReq.Page = page
+		pageReq.PageSize = pageSize
+
+		resp, err := m.List(ctx, &pageReq, callOpts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Datasets, resp.Total, nil
+	}, opts)
+}
+
+func (m *Datasets) Get(ctx context.Context, datasetID string, opts ...sdk.CallOption) (_ *sdk.Dataset, err error) {
+	defer m.record("Get", opts, datasetID)(&err)
+	if m.GetFunc == nil {
+		return nil, notMocked("Datasets.Get")
+	}
+	return m.GetFunc(ctx, datasetID)
+}
+
+func (m *Datasets) Update(ctx context.Context, datasetID string, req *sdk.UpdateDatasetRequest, opts ...sdk.CallOption) (_ *sdk.Dataset, err error) {
+	defer m.record("Update", opts, datasetID, req)(&err)
+	if m.UpdateFunc == nil {
+		return nil, notMocked("Datasets.Update")
+	}
+	return m.UpdateFunc(ctx, datasetID, req)
+}
+
+func (m *Datasets) Delete(ctx context.Context, datasetID string, opts ...sdk.CallOption) (err error) {
+	defer m.record("Delete", opts, datasetID)(&err)
+	if m.DeleteFunc == nil {
+		return notMocked("Datasets.Delete")
+	}
+	return m.DeleteFunc(ctx, datasetID)
+}
+
+func (m *Datasets) GetStats(ctx context.Context, datasetID string, opts ...sdk.CallOption) (_ *sdk.DatasetStats, err error) {
+	defer m.record("GetStats", opts, datasetID)(&err)
+	if m.GetStatsFunc == nil {
+		return nil, notMocked("Datasets.GetStats")
+	}
+	return m.GetStatsFunc(ctx, datasetID)
+}
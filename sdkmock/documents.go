@@ -0,0 +1,146 @@
+package sdkmock
+
+import (
+	"context"
+	"io/fs"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// Documents sdk.DocumentsAPI 的 mock，未设置 ListPagerFunc 时 ListPager 基于 List 分页
+type Documents struct {
+	UploadFunc                func(ctx context.Context, req *sdk.UploadDocumentRequest) (*sdk.UploadDocumentResponse, error)
+	ListFunc                  func(ctx context.Context, req *sdk.ListDocumentsRequest) (*sdk.ListDocumentsResponse, error)
+	ListPagerFunc             func(req *sdk.ListDocumentsRequest, opts *sdk.PagerOptions) *sdk.Pager[sdk.Document]
+	GetFunc                   func(ctx context.Context, datasetID, documentID string) (*sdk.Document, error)
+	UpdateFunc                func(ctx context.Context, req *sdk.UpdateDocumentRequest) (*sdk.Document, error)
+	DeleteFunc                func(ctx context.Context, datasetID, documentID string) error
+	BatchDeleteFunc           func(ctx context.Context, req *sdk.BatchDeleteDocumentsRequest) error
+	ReindexFunc               func(ctx context.Context, datasetID, documentID string) (*sdk.ReindexResponse, error)
+	WaitUntilProcessedFunc    func(ctx context.Context, datasetID, documentID string, opts *sdk.WaitOptions) (*sdk.Document, error)
+	WaitUntilAllProcessedFunc func(ctx context.Context, datasetID string, documentIDs []string, opts *sdk.WaitOptions) ([]sdk.Document, error)
+	UploadManyFunc            func(ctx context.Context, reqs <-chan *sdk.UploadDocumentRequest, opts *sdk.BulkUploadOptions) (*sdk.BulkUploadReport, error)
+	SyncDirFunc               func(ctx context.Context, datasetID, dir string, opts *sdk.SyncOptions) (*sdk.SyncPlan, error)
+	SyncFSFunc                func(ctx context.Context, datasetID string, fsys fs.FS, opts *sdk.SyncOptions) (*sdk.SyncPlan, error)
+
+	recorder
+}
+
+var _ sdk.DocumentsAPI = (*Documents)(nil)
+
+func (m *Documents) Upload(ctx context.Context, req *sdk.UploadDocumentRequest, opts ...sdk.CallOption) (_ *sdk.UploadDocumentResponse, err error) {
+	defer m.record("Upload", opts, req)(&err)
+	if m.UploadFunc == nil {
+		return nil, notMocked("Documents.Upload")
+	}
+	return m.UploadFunc(ctx, req)
+}
+
+func (m *Documents) List(ctx context.Context, req *sdk.ListDocumentsRequest, opts ...sdk.CallOption) (_ *sdk.ListDocumentsResponse, err error) {
+	defer m.record("List", opts, req)(&err)
+	if m.ListFunc == nil {
+		return nil, notMocked("Documents.List")
+	}
+	return m.ListFunc(ctx, req)
+}
+
+func (m *Documents) ListPager(req *sdk.ListDocumentsRequest, opts *sdk.PagerOptions, callOpts ...sdk.CallOption) *sdk.Pager[sdk.Document] {
+	m.record("ListPager", callOpts, req, opts)
+	if m.ListPagerFunc != nil {
+		return m.ListPagerFunc(req, opts)
+	}
+	return sdk.NewPager(func(ctx context.Context, page, pageSize int) ([]sdk.Document, int64, error) {
+		var pageReq sdk.ListDocumentsRequest
+		if req != nil {
+			pageReq = *req
+		}
+		pageReq.Page = page
+		pageReq.PageSize = pageSize
+
+		resp, err := m.List(ctx, &pageReq, callOpts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Documents, resp.Total, nil
+	}, opts)
+}
+
+func (m *Documents) Get(ctx context.Context, datasetID, documentID string, opts ...sdk.CallOption) (_ *sdk.Document, err error) {
+	defer m.record("Get", opts, datasetID, documentID)(&err)
+	if m.GetFunc == nil {
+		return nil, notMocked("Documents.Get")
+	}
+	return m.GetFunc(ctx, datasetID, documentID)
+}
+
+func (m *Documents) Update(ctx context.Context, req *sdk.UpdateDocumentRequest, opts ...sdk.CallOption) (_ *sdk.Document, err error) {
+	defer m.record("Update", opts, req)(&err)
+	if m.UpdateFunc == nil {
+		return nil, notMocked("Documents.Update")
+	}
+	return m.UpdateFunc(ctx, req)
+}
+
+func (m *Documents) Delete(ctx context.Context, datasetID, documentID string, opts ...sdk.CallOption) (err error) {
+	defer m.record("Delete", opts, datasetID, documentID)(&err)
+	if m.DeleteFunc == nil {
+		return notMocked("Documents.Delete")
+	}
+	return m.DeleteFunc(ctx, datasetID, documentID)
+}
+
+func (m *Documents) BatchDelete(ctx context.Context, req *sdk.BatchDeleteDocumentsRequest, opts ...sdk.CallOption) (err error) {
+	defer m.record("BatchDelete", opts, req)(&err)
+	if m.BatchDeleteFunc == nil {
+		return notMocked("Documents.BatchDelete")
+	}
+	return m.BatchDeleteFunc(ctx, req)
+}
+
+func (m *Documents) Reindex(ctx context.Context, datasetID, documentID string, opts ...sdk.CallOption) (_ *sdk.ReindexResponse, err error) {
+	defer m.record("Reindex", opts, datasetID, documentID)(&err)
+	if m.ReindexFunc == nil {
+		return nil, notMocked("Documents.Reindex")
+	}
+	return m.ReindexFunc(ctx, datasetID, documentID)
+}
+
+func (m *Documents) WaitUntilProcessed(ctx context.Context, datasetID, documentID string, opts *sdk.WaitOptions, callOpts ...sdk.CallOption) (_ *sdk.Document, err error) {
+	defer m.record("WaitUntilProcessed", callOpts, datasetID, documentID, opts)(&err)
+	if m.WaitUntilProcessedFunc == nil {
+		return nil, notMocked("Documents.WaitUntilProcessed")
+	}
+	return m.WaitUntilProcessedFunc(ctx, datasetID, documentID, opts)
+}
+
+func (m *Documents) WaitUntilAllProcessed(ctx context.Context, datasetID string, documentIDs []string, opts *sdk.WaitOptions, callOpts ...sdk.CallOption) (_ []sdk.Document, err error) {
+	defer m.record("WaitUntilAllProcessed", callOpts, datasetID, documentIDs, opts)(&err)
+	if m.WaitUntilAllProcessedFunc == nil {
+		return nil, notMocked("Documents.WaitUntilAllProcessed")
+	}
+	return m.WaitUntilAllProcessedFunc(ctx, datasetID, documentIDs, opts)
+}
+
+func (m *Documents) UploadMany(ctx context.Context, reqs <-chan *sdk.UploadDocumentRequest, opts *sdk.BulkUploadOptions, callOpts ...sdk.CallOption) (_ *sdk.BulkUploadReport, err error) {
+	defer m.record("UploadMany", callOpts, reqs, opts)(&err)
+	if m.UploadManyFunc == nil {
+		return nil, notMocked("Documents.UploadMany")
+	}
+	return m.UploadManyFunc(ctx, reqs, opts)
+}
+
+func (m *Documents) SyncDir(ctx context.Context, datasetID, dir string, opts *sdk.SyncOptions, callOpts ...sdk.CallOption) (_ *sdk.SyncPlan, err error) {
+	defer m.record("SyncDir", callOpts, datasetID, dir, opts)(&err)
+	if m.SyncDirFunc == nil {
+		return nil, notMocked("Documents.SyncDir")
+	}
+	return m.SyncDirFunc(ctx, datasetID, dir, opts)
+}
+
+func (m *Documents) SyncFS(ctx context.Context, datasetID string, fsys fs.FS, opts *sdk.SyncOptions, callOpts ...sdk.CallOption) (_ *sdk.SyncPlan, err error) {
+	defer m.record("SyncFS", callOpts, datasetID, fsys, opts)(&err)
+	if m.SyncFSFunc == nil {
+		return nil, notMocked("Documents.SyncFS")
+	}
+	return m.SyncFSFunc(ctx, datasetID, fsys, opts)
+}
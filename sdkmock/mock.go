@@ -0,0 +1,151 @@
+// Package sdkmock 提供 SDK 服务接口的手写 mock 实现。
+//
+// 每个 mock 为接口的每个方法提供一个 XxxFunc 字段，调用时记录参数并执行对应函数；
+// 未设置的函数返回 ErrNotMocked。方法的 sdk.CallOption 参数记录在 Call.Opts 中，不会传给 XxxFunc；
+// 调用结束后按 XxxFunc 返回的错误填充 sdk.WithResponseMeta。
+// Client 聚合全部 mock 并实现 sdk.API：
+//
+//	mock := sdkmock.NewClient()
+//	mock.Datasets.GetFunc = func(ctx context.Context, id string) (*sdk.Dataset, error) {
+//		return &sdk.Dataset{ID: id, Name: "测试"}, nil
+//	}
+//
+//	svc := NewService(mock) // 业务代码依赖 sdk.API
+//	// ...
+//	calls := mock.Datasets.CallsTo("Get")
+//	timeout := calls[0].Operation().Timeout // sdk.WithCallTimeout 设置的超时
+package sdkmock
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// ErrNotMocked 调用了未设置实现的方法
+var ErrNotMocked = errors.New("sdkmock: method not mocked")
+
+func notMocked(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotMocked, method)
+}
+
+// Call 一次方法调用，Args 为除 context 和调用选项以外的参数
+type Call struct {
+	Method string
+	Args   []interface{}
+
+	// 调用时传入的 sdk.CallOption
+	Opts []sdk.CallOption
+}
+
+// Operation 返回应用了 Opts 的 sdk.Operation，用于检查 WithHeader、WithCallTimeout 等选项设置的值
+func (c Call) Operation() *sdk.Operation {
+	op := &sdk.Operation{}
+	for _, opt := range c.Opts {
+		opt(op)
+	}
+	return op
+}
+
+// recorder 并发安全的调用记录
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+// record 记录一次调用，返回的函数在调用结束后按返回的错误填充 WithResponseMeta
+func (r *recorder) record(method string, opts []sdk.CallOption, args ...interface{}) func(err *error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, Call{Method: method, Args: args, Opts: append([]sdk.CallOption(nil), opts...)})
+	r.mu.Unlock()
+
+	return func(err *error) {
+		fillResponseMeta(opts, *err)
+	}
+}
+
+// fillResponseMeta 模拟真实调用填充 WithResponseMeta：成功时状态码为 200，
+// 返回 *sdk.APIError 时使用其中的状态码和请求 ID，其他错误的状态码为 0
+func fillResponseMeta(opts []sdk.CallOption, err error) {
+	op := Call{Opts: opts}.Operation()
+	meta := op.ResponseMeta()
+	if meta == nil {
+		return
+	}
+
+	*meta = sdk.ResponseMeta{RequestID: op.Header.Get("X-Request-Id")}
+	var apiErr *sdk.APIError
+	switch {
+	case err == nil:
+		meta.StatusCode = http.StatusOK
+	case errors.As(err, &apiErr):
+		meta.StatusCode = apiErr.StatusCode
+		if apiErr.RequestID != "" {
+			meta.RequestID = apiErr.RequestID
+		}
+	}
+}
+
+// Calls 返回全部调用记录
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// CallsTo 返回指定方法的调用记录
+func (r *recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var calls []Call
+	for _, c := range r.calls {
+		if c.Method == method {
+			calls = append(calls, c)
+		}
+	}
+	return calls
+}
+
+// ResetCalls 清空调用记录
+func (r *recorder) ResetCalls() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// Client 实现 sdk.API 的 mock，聚合各个服务的 mock
+type Client struct {
+	Models    *Models
+	Datasets  *Datasets
+	Documents *Documents
+	Search    *Search
+	QA        *QA
+	Generate  *Generate
+	Health    *Health
+}
+
+var _ sdk.API = (*Client)(nil)
+
+// NewClient 创建所有服务 mock 均已初始化的 Client
+func NewClient() *Client {
+	return &Client{
+		Models:    &Models{},
+		Datasets:  &Datasets{},
+		Documents: &Documents{},
+		Search:    &Search{},
+		QA:        &QA{},
+		Generate:  &Generate{},
+		Health:    &Health{},
+	}
+}
+
+func (c *Client) ModelsAPI() sdk.ModelsAPI       { return c.Models }
+func (c *Client) DatasetsAPI() sdk.DatasetsAPI   { return c.Datasets }
+func (c *Client) DocumentsAPI() sdk.DocumentsAPI { return c.Documents }
+func (c *Client) SearchAPI() sdk.SearchAPI       { return c.Search }
+func (c *Client) QAAPI() sdk.QAAPI               { return c.QA }
+func (c *Client) GenerateAPI() sdk.GenerateAPI   { return c.Generate }
+func (c *Client) HealthAPI() sdk.HealthAPI       { return c.Health }
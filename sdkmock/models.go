@@ -0,0 +1,109 @@
+package sdkmock
+
+import (
+	"context"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// Models sdk.ModelsAPI 的 mock，未设置 ListPagerFunc 时 ListPager 基于 List 分页
+type Models struct {
+	CreateFunc             func(ctx context.Context, req *sdk.CreateModelRequest) (*sdk.AIModel, error)
+	ListFunc               func(ctx context.Context, req *sdk.ListModelsRequest) (*sdk.ListModelsResponse, error)
+	ListPagerFunc          func(req *sdk.ListModelsRequest, opts *sdk.PagerOptions) *sdk.Pager[sdk.AIModel]
+	GetFunc                func(ctx context.Context, modelID string) (*sdk.AIModel, error)
+	UpdateFunc             func(ctx context.Context, modelID string, req *sdk.UpdateModelRequest) (*sdk.AIModel, error)
+	DeleteFunc             func(ctx context.Context, modelID string) error
+	ListProviderModelsFunc func(ctx context.Context, req *sdk.ListProviderModelsRequest) (interface{}, error)
+	CheckFunc              func(ctx context.Context, req *sdk.CheckModelRequest) (*sdk.CheckModelResponse, error)
+	UpsertFunc             func(ctx context.Context, req *sdk.UpsertModelRequest) (*sdk.UpsertModelResponse, error)
+
+	recorder
+}
+
+var _ sdk.ModelsAPI = (*Models)(nil)
+
+func (m *Models) Create(ctx context.Context, req *sdk.CreateModelRequest, opts ...sdk.CallOption) (_ *sdk.AIModel, err error) {
+	defer m.record("Create", opts, req)(&err)
+	if m.CreateFunc == nil {
+		return nil, notMocked("Models.Create")
+	}
+	return m.CreateFunc(ctx, req)
+}
+
+func (m *Models) List(ctx context.Context, req *sdk.ListModelsRequest, opts ...sdk.CallOption) (_ *sdk.ListModelsResponse, err error) {
+	defer m.record("List", opts, req)(&err)
+	if m.ListFunc == nil {
+		return nil, notMocked("Models.List")
+	}
+	return m.ListFunc(ctx, req)
+}
+
+func (m *Models) ListPager(req *sdk.ListModelsRequest, opts *sdk.PagerOptions, callOpts ...sdk.CallOption) *sdk.Pager[sdk.AIModel] {
+	m.record("ListPager", callOpts, req, opts)
+	if m.ListPagerFunc != nil {
+		return m.ListPagerFunc(req, opts)
+	}
+	return sdk.NewPager(func(ctx context.Context, page, pageSize int) ([]sdk.AIModel, int64, error) {
+		var pageReq sdk.ListModelsRequest
+		if req != nil {
+			pageReq = *req
+		}
+		pageReq.Page = page
+		pageReq.PageSize = pageSize
+
+		resp, err := m.List(ctx, &pageReq, callOpts...)
+		if err != nil {
+			return nil, 0, err
+		}
+		return resp.Models, resp.Total, nil
+	}, opts)
+}
+
+func (m *Models) Get(ctx context.Context, modelID string, opts ...sdk.CallOption) (_ *sdk.AIModel, err error) {
+	defer m.record("Get", opts, modelID)(&err)
+	if m.GetFunc == nil {
+		return nil, notMocked("Models.Get")
+	}
+	return m.GetFunc(ctx, modelID)
+}
+
+func (m *Models) Update(ctx context.Context, modelID string, req *sdk.UpdateModelRequest, opts ...sdk.CallOption) (_ *sdk.AIModel, err error) {
+	defer m.record("Update", opts, modelID, req)(&err)
+	if m.UpdateFunc == nil {
+		return nil, notMocked("Models.Update")
+	}
+	return m.UpdateFunc(ctx, modelID, req)
+}
+
+func (m *Models) Delete(ctx context.Context, modelID string, opts ...sdk.CallOption) (err error) {
+	defer m.record("Delete", opts, modelID)(&err)
+	if m.DeleteFunc == nil {
+		return notMocked("Models.Delete")
+	}
+	return m.DeleteFunc(ctx, modelID)
+}
+
+func (m *Models) ListProviderModels(ctx context.Context, req *sdk.ListProviderModelsRequest, opts ...sdk.CallOption) (_ interface{}, err error) {
+	defer m.record("ListProviderModels", opts, req)(&err)
+	if m.ListProviderModelsFunc == nil {
+		return nil, notMocked("Models.ListProviderModels")
+	}
+	return m.ListProviderModelsFunc(ctx, req)
+}
+
+func (m *Models) Check(ctx context.Context, req *sdk.CheckModelRequest, opts ...sdk.CallOption) (_ *sdk.CheckModelResponse, err error) {
+	defer m.record("Check", opts, req)(&err)
+	if m.CheckFunc == nil {
+		return nil, notMocked("Models.Check")
+	}
+	return m.CheckFunc(ctx, req)
+}
+
+func (m *Models) Upsert(ctx context.Context, req *sdk.UpsertModelRequest, opts ...sdk.CallOption) (_ *sdk.UpsertModelResponse, err error) {
+	defer m.record("Upsert", opts, req)(&err)
+	if m.UpsertFunc == nil {
+		return nil, notMocked("Models.Upsert")
+	}
+	return m.UpsertFunc(ctx, req)
+}
@@ -0,0 +1,93 @@
+package sdkmock
+
+import (
+	"context"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// Search sdk.SearchAPI 的 mock
+type Search struct {
+	RetrieveFunc func(ctx context.Context, req *sdk.RetrieveRequest) (*sdk.SearchResponse, error)
+
+	recorder
+}
+
+var _ sdk.SearchAPI = (*Search)(nil)
+
+func (m *Search) Retrieve(ctx context.Context, req *sdk.RetrieveRequest, opts ...sdk.CallOption) (_ *sdk.SearchResponse, err error) {
+	defer m.record("Retrieve", opts, req)(&err)
+	if m.RetrieveFunc == nil {
+		return nil, notMocked("Search.Retrieve")
+	}
+	return m.RetrieveFunc(ctx, req)
+}
+
+// QA sdk.QAAPI 的 mock，AskStreamFunc 可以用 sdk.NewEventStream 构造返回的流
+type QA struct {
+	AskFunc       func(ctx context.Context, req *sdk.QARequest) (*sdk.QAResponse, error)
+	AskStreamFunc func(ctx context.Context, req *sdk.QARequest) (*sdk.QAStream, error)
+
+	recorder
+}
+
+var _ sdk.QAAPI = (*QA)(nil)
+
+func (m *QA) Ask(ctx context.Context, req *sdk.QARequest, opts ...sdk.CallOption) (_ *sdk.QAResponse, err error) {
+	defer m.record("Ask", opts, req)(&err)
+	if m.AskFunc == nil {
+		return nil, notMocked("QA.Ask")
+	}
+	return m.AskFunc(ctx, req)
+}
+
+func (m *QA) AskStream(ctx context.Context, req *sdk.QARequest, opts ...sdk.CallOption) (_ *sdk.QAStream, err error) {
+	defer m.record("AskStream", opts, req)(&err)
+	if m.AskStreamFunc == nil {
+		return nil, notMocked("QA.AskStream")
+	}
+	return m.AskStreamFunc(ctx, req)
+}
+
+// Generate sdk.GenerateAPI 的 mock，GenerateStreamFunc 可以用 sdk.NewEventStream 构造返回的流
+type Generate struct {
+	GenerateFunc       func(ctx context.Context, req *sdk.GenerateRequest) (*sdk.GenerateResponse, error)
+	GenerateStreamFunc func(ctx context.Context, req *sdk.GenerateRequest) (*sdk.GenerateStream, error)
+
+	recorder
+}
+
+var _ sdk.GenerateAPI = (*Generate)(nil)
+
+func (m *Generate) Generate(ctx context.Context, req *sdk.GenerateRequest, opts ...sdk.CallOption) (_ *sdk.GenerateResponse, err error) {
+	defer m.record("Generate", opts, req)(&err)
+	if m.GenerateFunc == nil {
+		return nil, notMocked("Generate.Generate")
+	}
+	return m.GenerateFunc(ctx, req)
+}
+
+func (m *Generate) GenerateStream(ctx context.Context, req *sdk.GenerateRequest, opts ...sdk.CallOption) (_ *sdk.GenerateStream, err error) {
+	defer m.record("GenerateStream", opts, req)(&err)
+	if m.GenerateStreamFunc == nil {
+		return nil, notMocked("Generate.GenerateStream")
+	}
+	return m.GenerateStreamFunc(ctx, req)
+}
+
+// Health sdk.HealthAPI 的 mock
+type Health struct {
+	CheckFunc func(ctx context.Context) (*sdk.HealthResponse, error)
+
+	recorder
+}
+
+var _ sdk.HealthAPI = (*Health)(nil)
+
+func (m *Health) Check(ctx context.Context, opts ...sdk.CallOption) (_ *sdk.HealthResponse, err error) {
+	defer m.record("Check", opts)(&err)
+	if m.CheckFunc == nil {
+		return nil, notMocked("Health.Check")
+	}
+	return m.CheckFunc(ctx)
+}
@@ -0,0 +1,203 @@
+package sdktest
+
+import (
+	"net/http"
+	"time"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// AddDataset 直接添加数据集，未设置的 ID、Status 和时间字段会自动填充
+func (s *Server) AddDataset(dataset sdk.Dataset) sdk.Dataset {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if dataset.ID == "" {
+		dataset.ID = s.newID("dataset")
+	}
+	if dataset.Status == "" {
+		dataset.Status = "active"
+	}
+	now := time.Now()
+	if dataset.CreatedAt.IsZero() {
+		dataset.CreatedAt = now
+	}
+	if dataset.UpdatedAt.IsZero() {
+		dataset.UpdatedAt = now
+	}
+	s.datasets = append(s.datasets, &dataset)
+	return dataset
+}
+
+func (s *Server) createDataset(_ *http.Request, body []byte) (interface{}, error) {
+	var req sdk.CreateDatasetRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, errorf(http.StatusBadRequest, "name is required")
+	}
+
+	dataset := sdk.Dataset{
+		Name:            req.Name,
+		Description:     req.Description,
+		SparseModelID:   req.SparseModelID,
+		AnalysisModelID: req.AnalysisModelID,
+		RerankerModelID: req.RerankerModelID,
+		VisionModelID:   req.VisionModelID,
+		Config:          req.Config,
+	}
+	if dataset.Config.ChunkSize == 0 {
+		dataset.Config.ChunkSize = 512
+	}
+
+	s.mu.Lock()
+	if req.DenseModelID != nil {
+		if _, err := s.findModel(*req.DenseModelID); err != nil {
+			s.mu.Unlock()
+			return nil, errorf(http.StatusBadRequest, "dense model %s not found", *req.DenseModelID)
+		}
+		dataset.DenseModelID = *req.DenseModelID
+	} else {
+		for _, m := range s.models {
+			if m.ModelType == "embedding" && m.IsDefault {
+				dataset.DenseModelID = m.ID
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	return s.AddDataset(dataset), nil
+}
+
+func (s *Server) listDatasets(r *http.Request, _ []byte) (interface{}, error) {
+	q := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var datasets []sdk.Dataset
+	for _, d := range s.datasets {
+		if v := q.Get("status"); v != "" && d.Status != v {
+			continue
+		}
+		datasets = append(datasets, *d)
+	}
+
+	page, pageSize, items, err := paginate(datasets, q)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.ListDatasetsResponse{
+		Datasets: items,
+		Total:    int64(len(datasets)),
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *Server) getDataset(id string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.findDataset(id)
+	if err != nil {
+		return nil, err
+	}
+	return *d, nil
+}
+
+func (s *Server) updateDataset(id string, body []byte) (interface{}, error) {
+	var req sdk.UpdateDatasetRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.findDataset(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		d.Name = *req.Name
+	}
+	if req.Description != nil {
+		d.Description = *req.Description
+	}
+	if req.DenseModelID != nil {
+		d.DenseModelID = *req.DenseModelID
+	}
+	if req.SparseModelID != nil {
+		d.SparseModelID = req.SparseModelID
+	}
+	if req.AnalysisModelID != nil {
+		d.AnalysisModelID = req.AnalysisModelID
+	}
+	if req.RerankerModelID != nil {
+		d.RerankerModelID = req.RerankerModelID
+	}
+	if req.VisionModelID != nil {
+		d.VisionModelID = req.VisionModelID
+	}
+	if req.Config != nil {
+		d.Config = *req.Config
+	}
+	if req.Status != nil {
+		d.Status = *req.Status
+	}
+	d.UpdatedAt = time.Now()
+	return *d, nil
+}
+
+func (s *Server) deleteDataset(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, d := range s.datasets {
+		if d.ID == id {
+			s.datasets = append(s.datasets[:i:i], s.datasets[i+1:]...)
+			delete(s.documents, id)
+			return nil
+		}
+	}
+	return errorf(http.StatusNotFound, "dataset %s not found", id)
+}
+
+func (s *Server) datasetStats(id string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	d, err := s.findDataset(id)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := sdk.DatasetStats{Dataset: *d}
+	for _, doc := range s.documents[id] {
+		stats.TotalDocuments++
+		stats.TotalFileSize += doc.FileSize
+		switch doc.Status {
+		case sdk.DocumentStatusPending:
+			stats.PendingDocs++
+		case sdk.DocumentStatusProcessing:
+			stats.ProcessingDocs++
+		case sdk.DocumentStatusCompleted:
+			stats.CompletedDocs++
+		case sdk.DocumentStatusFailed:
+			stats.FailedDocs++
+		}
+	}
+	return stats, nil
+}
+
+// findDataset 按 ID 查找数据集，调用方需持有锁
+func (s *Server) findDataset(id string) (*sdk.Dataset, error) {
+	for _, d := range s.datasets {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "dataset %s not found", id)
+}
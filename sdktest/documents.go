@@ -0,0 +1,347 @@
+package sdktest
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// document 文档及其处理状态
+type document struct {
+	sdk.Document
+	content []byte
+	chunks  []chunk
+	steps   int // 剩余停留在 processing 的查询次数
+}
+
+// chunk 文档分块
+type chunk struct {
+	id      string
+	section string
+	content string
+}
+
+// SetDocumentStatus 直接设置文档状态，之后不再自动推进，可用于模拟处理失败或卡住的文档
+func (s *Server) SetDocumentStatus(datasetID, documentID, status, progressMsg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.findDocument(datasetID, documentID)
+	if err != nil {
+		return err
+	}
+	doc.Status = status
+	doc.ProgressMsg = progressMsg
+	doc.steps = -1
+	doc.UpdatedAt = time.Now()
+	if status == sdk.DocumentStatusCompleted {
+		doc.chunks = splitChunks(doc.ID, doc.content)
+	}
+	return nil
+}
+
+func (s *Server) uploadDocument(datasetID string, r *http.Request) (interface{}, error) {
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		return nil, errorf(http.StatusBadRequest, "invalid multipart form: %v", err)
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "file is required")
+	}
+	defer file.Close()
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return nil, errorf(http.StatusBadRequest, "failed to read file: %v", err)
+	}
+
+	var tags []string
+	if v := r.FormValue("tags"); v != "" {
+		if err := json.Unmarshal([]byte(v), &tags); err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid tags: %v", err)
+		}
+	}
+	var metadata map[string]interface{}
+	if v := r.FormValue("metadata"); v != "" {
+		if err := json.Unmarshal([]byte(v), &metadata); err != nil {
+			return nil, errorf(http.StatusBadRequest, "invalid metadata: %v", err)
+		}
+	}
+	title := r.FormValue("title")
+	if title == "" {
+		title = header.Filename
+	}
+	hash := sha256.Sum256(content)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.findDataset(datasetID); err != nil {
+		return nil, err
+	}
+
+	var doc *document
+	if id := r.FormValue("document_id"); id != "" {
+		if doc, err = s.findDocument(datasetID, id); err != nil {
+			return nil, err
+		}
+		if tags != nil {
+			doc.Tags = tags
+		}
+		if metadata != nil {
+			doc.Metadata = sdk.JSON{Data: metadata}
+		}
+	} else {
+		doc = &document{Document: sdk.Document{
+			ID:        s.newID("doc"),
+			DatasetID: datasetID,
+			Tags:      tags,
+			Metadata:  sdk.JSON{Data: metadata},
+			CreatedAt: time.Now(),
+		}}
+		s.documents[datasetID] = append(s.documents[datasetID], doc)
+	}
+
+	doc.Title = title
+	doc.Filename = header.Filename
+	doc.FilePath = fmt.Sprintf("%s/%s/%s", datasetID, doc.ID, header.Filename)
+	doc.FileHash = hex.EncodeToString(hash[:])
+	doc.FileSize = int64(len(content))
+	doc.content = content
+	s.startProcessing(doc)
+
+	return sdk.UploadDocumentResponse{
+		DocumentID: doc.ID,
+		Status:     doc.Status,
+		Message:    "document uploaded",
+		Filename:   doc.Filename,
+		Title:      doc.Title,
+		Size:       doc.FileSize,
+	}, nil
+}
+
+func (s *Server) listDocuments(datasetID string, q url.Values) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.findDataset(datasetID); err != nil {
+		return nil, err
+	}
+
+	var ids map[string]bool
+	if v := q.Get("document_ids"); v != "" {
+		ids = make(map[string]bool)
+		for _, id := range strings.Split(v, ",") {
+			ids[id] = true
+		}
+	}
+
+	var docs []*document
+	for _, doc := range s.documents[datasetID] {
+		if ids == nil || ids[doc.ID] {
+			docs = append(docs, doc)
+		}
+	}
+
+	page, pageSize, items, err := paginate(docs, q)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]sdk.Document, len(items))
+	for i, doc := range items {
+		s.advance(doc)
+		result[i] = doc.Document
+	}
+	return sdk.ListDocumentsResponse{
+		Documents: result,
+		Total:     int64(len(docs)),
+		Page:      page,
+		PageSize:  pageSize,
+	}, nil
+}
+
+func (s *Server) getDocument(datasetID, id string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.findDocument(datasetID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.advance(doc)
+	return doc.Document, nil
+}
+
+func (s *Server) updateDocument(datasetID, id string, body []byte) (interface{}, error) {
+	var req sdk.UpdateDocumentRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.findDocument(datasetID, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Metadata != nil {
+		doc.Metadata = sdk.JSON{Data: req.Metadata}
+	}
+	if req.Tags != nil {
+		doc.Tags = req.Tags
+	}
+	doc.UpdatedAt = time.Now()
+	return doc.Document, nil
+}
+
+func (s *Server) deleteDocument(datasetID, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.findDocument(datasetID, id); err != nil {
+		return err
+	}
+	s.removeDocuments(datasetID, map[string]bool{id: true})
+	return nil
+}
+
+func (s *Server) batchDeleteDocuments(datasetID string, body []byte) error {
+	var req sdk.BatchDeleteDocumentsRequest
+	if err := decode(body, &req); err != nil {
+		return err
+	}
+	if len(req.DocumentIDs) == 0 {
+		return errorf(http.StatusBadRequest, "document_ids is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.findDataset(datasetID); err != nil {
+		return err
+	}
+	ids := make(map[string]bool, len(req.DocumentIDs))
+	for _, id := range req.DocumentIDs {
+		ids[id] = true
+	}
+	s.removeDocuments(datasetID, ids)
+	return nil
+}
+
+func (s *Server) reindexDocument(datasetID, id string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	doc, err := s.findDocument(datasetID, id)
+	if err != nil {
+		return nil, err
+	}
+	s.startProcessing(doc)
+	return sdk.ReindexResponse{Message: "reindex started", DocumentID: doc.ID}, nil
+}
+
+// findDocument 按 ID 查找文档，调用方需持有锁
+func (s *Server) findDocument(datasetID, id string) (*document, error) {
+	if _, err := s.findDataset(datasetID); err != nil {
+		return nil, err
+	}
+	for _, doc := range s.documents[datasetID] {
+		if doc.ID == id {
+			return doc, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "document %s not found", id)
+}
+
+// removeDocuments 删除数据集中的文档，调用方需持有锁
+func (s *Server) removeDocuments(datasetID string, ids map[string]bool) {
+	var kept []*document
+	for _, doc := range s.documents[datasetID] {
+		if !ids[doc.ID] {
+			kept = append(kept, doc)
+		}
+	}
+	s.documents[datasetID] = kept
+}
+
+// startProcessing 将文档重置为 pending，steps 为 0 时立即完成处理，调用方需持有锁
+func (s *Server) startProcessing(doc *document) {
+	doc.Status = sdk.DocumentStatusPending
+	doc.ProgressMsg = ""
+	doc.chunks = nil
+	doc.steps = s.processingSteps
+	doc.UpdatedAt = time.Now()
+	if s.processingSteps <= 0 {
+		s.finishProcessing(doc)
+	}
+}
+
+// advance 文档被查询一次后推进处理状态，调用方需持有锁
+func (s *Server) advance(doc *document) {
+	switch doc.Status {
+	case sdk.DocumentStatusPending:
+		if doc.steps < 0 {
+			return
+		}
+		doc.Status = sdk.DocumentStatusProcessing
+		doc.ProgressMsg = "parsing"
+		doc.UpdatedAt = time.Now()
+	case sdk.DocumentStatusProcessing:
+		if doc.steps < 0 {
+			return
+		}
+		if doc.steps--; doc.steps <= 0 {
+			s.finishProcessing(doc)
+		}
+	}
+}
+
+// finishProcessing 运行文档处理函数并设置最终状态，调用方需持有锁
+func (s *Server) finishProcessing(doc *document) {
+	doc.UpdatedAt = time.Now()
+	if s.processor != nil {
+		if err := s.processor(doc.Document, doc.content); err != nil {
+			doc.Status = sdk.DocumentStatusFailed
+			doc.ProgressMsg = err.Error()
+			return
+		}
+	}
+	doc.Status = sdk.DocumentStatusCompleted
+	doc.ProgressMsg = ""
+	doc.chunks = splitChunks(doc.ID, doc.content)
+}
+
+// splitChunks 按空行将文本切分为块，以 # 开头的行作为后续块的章节标题
+func splitChunks(documentID string, content []byte) []chunk {
+	var chunks []chunk
+	var section string
+	for _, para := range strings.Split(strings.ReplaceAll(string(content), "\r\n", "\n"), "\n\n") {
+		para = strings.TrimSpace(para)
+		if para == "" {
+			continue
+		}
+		if strings.HasPrefix(para, "#") {
+			heading, rest, _ := strings.Cut(para, "\n")
+			section = strings.TrimSpace(strings.TrimLeft(heading, "#"))
+			if para = strings.TrimSpace(rest); para == "" {
+				continue
+			}
+		}
+		chunks = append(chunks, chunk{
+			id:      fmt.Sprintf("%s-chunk-%d", documentID, len(chunks)+1),
+			section: section,
+			content: para,
+		})
+	}
+	return chunks
+}
@@ -0,0 +1,119 @@
+package sdktest
+
+import (
+	"net/http"
+	"path"
+	"strconv"
+	"time"
+)
+
+// Fault 错误注入规则
+//
+// 匹配的请求会先等待 Latency，然后按 StatusCode 或 CloseConnection 返回错误；
+// 两者都未设置时只注入延迟，请求随后正常处理。SDK 自动重试时每次重试都算一个请求。
+type Fault struct {
+	// 请求方法，为空时匹配所有方法
+	Method string
+
+	// 请求路径，支持 path.Match 模式（例如 /api/v1/datasets/*/documents），为空时匹配所有路径
+	Path string
+
+	// 响应前的延迟，请求被取消时提前结束
+	Latency time.Duration
+
+	// 返回的状态码，例如 500、503 或 429
+	StatusCode int
+
+	// 错误信息，为空时使用状态码对应的文本
+	Message string
+
+	// 写入 Retry-After 响应头，按秒向上取整
+	RetryAfter time.Duration
+
+	// 不返回响应直接关闭连接，用于模拟网络错误
+	CloseConnection bool
+
+	// 生效次数，0 表示一直生效
+	Times int
+}
+
+// faultState 带剩余次数的错误注入规则
+type faultState struct {
+	Fault
+	remaining int
+}
+
+// InjectFault 添加错误注入规则，多条规则匹配时使用最先添加的一条
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &faultState{Fault: f, remaining: f.Times})
+}
+
+// ClearFaults 移除所有错误注入规则
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault 查找匹配的规则并扣减次数，调用方需持有锁
+func (s *Server) matchFault(method, urlPath string) *faultState {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, urlPath); !ok {
+				continue
+			}
+		}
+
+		if f.Times > 0 {
+			f.remaining--
+			if f.remaining <= 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+	return nil
+}
+
+// apply 执行错误注入，返回 false 表示已经写入响应，请求不再继续处理
+func (f *faultState) apply(w http.ResponseWriter, r *http.Request) bool {
+	if f.Latency > 0 {
+		timer := time.NewTimer(f.Latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-r.Context().Done():
+			return false
+		}
+	}
+
+	if f.CloseConnection {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return false
+			}
+		}
+		panic(http.ErrAbortHandler)
+	}
+
+	if f.StatusCode == 0 {
+		return true
+	}
+
+	if f.RetryAfter > 0 {
+		seconds := (f.RetryAfter + time.Second - 1) / time.Second
+		w.Header().Set("Retry-After", strconv.Itoa(int(seconds)))
+	}
+	msg := f.Message
+	if msg == "" {
+		msg = http.StatusText(f.StatusCode)
+	}
+	writeError(w, errorf(f.StatusCode, "%s", msg))
+	return false
+}
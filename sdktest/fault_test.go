@@ -0,0 +1,130 @@
+package sdktest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func TestInjectFault(t *testing.T) {
+	tests := []struct {
+		name       string
+		fault      Fault
+		calls      []string // 依次请求的路径
+		wantStatus []int
+	}{
+		{
+			name:       "times limits the fault",
+			fault:      Fault{Path: "/health", StatusCode: 503, Times: 2},
+			calls:      []string{"/health", "/health", "/health"},
+			wantStatus: []int{503, 503, 200},
+		},
+		{
+			name:       "zero times is permanent",
+			fault:      Fault{StatusCode: 500},
+			calls:      []string{"/health", "/api/v1/datasets", "/health"},
+			wantStatus: []int{500, 500, 500},
+		},
+		{
+			name:       "path pattern",
+			fault:      Fault{Path: "/api/v1/datasets/*", StatusCode: 429},
+			calls:      []string{"/api/v1/datasets", "/api/v1/datasets/ds-1"},
+			wantStatus: []int{200, 429},
+		},
+		{
+			name:       "method filter",
+			fault:      Fault{Method: "POST", StatusCode: 500},
+			calls:      []string{"/health"},
+			wantStatus: []int{200},
+		},
+		{
+			name:       "latency only",
+			fault:      Fault{Path: "/health", Latency: 10 * time.Millisecond},
+			calls:      []string{"/health"},
+			wantStatus: []int{200},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := NewServer()
+			defer srv.Close()
+			srv.AddDataset(sdk.Dataset{ID: "ds-1", Name: "docs"})
+			srv.InjectFault(tt.fault)
+
+			for i, path := range tt.calls {
+				start := time.Now()
+				resp := rawCall(t, srv, "GET", path, "", nil)
+				if resp.status != tt.wantStatus[i] {
+					t.Errorf("call %d GET %s: status = %d, want %d", i+1, path, resp.status, tt.wantStatus[i])
+				}
+				if elapsed := time.Since(start); elapsed < tt.fault.Latency {
+					t.Errorf("call %d returned after %s, want at least %s", i+1, elapsed, tt.fault.Latency)
+				}
+			}
+			if got := len(srv.Requests()); got != len(tt.calls) {
+				t.Errorf("recorded requests = %d, want %d including faulted ones", got, len(tt.calls))
+			}
+		})
+	}
+}
+
+func TestInjectFaultDetails(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+
+	// 第一条匹配的规则生效
+	srv.InjectFault(Fault{Path: "/health", StatusCode: 429, Message: "slow down", RetryAfter: 1500 * time.Millisecond, Times: 1})
+	srv.InjectFault(Fault{Path: "/health", StatusCode: 500, Times: 1})
+
+	resp := rawCall(t, srv, "GET", "/health", "", nil)
+	if resp.status != 429 || resp.body.Message != "slow down" || resp.header.Get("Retry-After") != "2" {
+		t.Errorf("first response = %d %q Retry-After %q, want 429 slow down with Retry-After 2",
+			resp.status, resp.body.Message, resp.header.Get("Retry-After"))
+	}
+	resp = rawCall(t, srv, "GET", "/health", "", nil)
+	if resp.status != 500 || resp.body.Message != http.StatusText(500) {
+		t.Errorf("second response = %d %q, want 500 with the default message", resp.status, resp.body.Message)
+	}
+
+	srv.InjectFault(Fault{StatusCode: 500})
+	srv.ClearFaults()
+	if resp := rawCall(t, srv, "GET", "/health", "", nil); resp.status != 200 {
+		t.Errorf("status after ClearFaults = %d, want 200", resp.status)
+	}
+}
+
+func TestInjectFaultCloseConnection(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.InjectFault(Fault{Path: "/health", CloseConnection: true, Times: 1})
+	client := srv.Client(sdk.WithRetryPolicy(nil))
+
+	var transportErr *sdk.TransportError
+	if _, err := client.Health.Check(context.Background()); !errors.As(err, &transportErr) {
+		t.Fatalf("err = %v, want *sdk.TransportError", err)
+	}
+	if _, err := client.Health.Check(context.Background()); err != nil {
+		t.Errorf("second Check: %v", err)
+	}
+}
+
+func TestInjectFaultLatencyHonorsCancel(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.InjectFault(Fault{Path: "/health", Latency: time.Minute})
+	client := srv.Client(sdk.WithRetryPolicy(nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.Health.Check(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("returned after %s, want the injected latency to end with the request", elapsed)
+	}
+}
@@ -0,0 +1,276 @@
+package sdktest
+
+import (
+	"net/http"
+	"time"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// AddModel 直接添加模型，未设置的 ID、Status 和时间字段会自动填充
+func (s *Server) AddModel(model sdk.AIModel) sdk.AIModel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if model.ID == "" {
+		model.ID = s.newID("model")
+	}
+	if model.Status == "" {
+		model.Status = "active"
+	}
+	now := time.Now()
+	if model.CreatedAt.IsZero() {
+		model.CreatedAt = now
+	}
+	if model.UpdatedAt.IsZero() {
+		model.UpdatedAt = now
+	}
+	s.models = append(s.models, &model)
+	if model.IsDefault {
+		s.setDefaultModel(&model)
+	}
+	return model
+}
+
+func (s *Server) createModel(_ *http.Request, body []byte) (interface{}, error) {
+	var req sdk.CreateModelRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Name == "" || req.ModelType == "" || req.Provider == "" || req.ModelName == "" {
+		return nil, errorf(http.StatusBadRequest, "name, model_type, provider and model_name are required")
+	}
+
+	return s.AddModel(sdk.AIModel{
+		Name:         req.Name,
+		Description:  req.Description,
+		ModelType:    req.ModelType,
+		Provider:     req.Provider,
+		ModelName:    req.ModelName,
+		Config:       req.Config,
+		Capabilities: req.Capabilities,
+		IsDefault:    req.IsDefault,
+	}), nil
+}
+
+func (s *Server) listModels(r *http.Request, _ []byte) (interface{}, error) {
+	q := r.URL.Query()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var models []sdk.AIModel
+	for _, m := range s.models {
+		if v := q.Get("model_type"); v != "" && m.ModelType != v {
+			continue
+		}
+		if v := q.Get("provider"); v != "" && m.Provider != v {
+			continue
+		}
+		if v := q.Get("status"); v != "" && m.Status != v {
+			continue
+		}
+		models = append(models, *m)
+	}
+
+	page, pageSize, items, err := paginate(models, q)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.ListModelsResponse{
+		Models:   items,
+		Total:    int64(len(models)),
+		Page:     page,
+		PageSize: pageSize,
+	}, nil
+}
+
+func (s *Server) getModel(id string) (interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.findModel(id)
+	if err != nil {
+		return nil, err
+	}
+	return *m, nil
+}
+
+func (s *Server) updateModel(id string, body []byte) (interface{}, error) {
+	var req sdk.UpdateModelRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, err := s.findModel(id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name != nil {
+		m.Name = *req.Name
+	}
+	if req.Description != nil {
+		m.Description = *req.Description
+	}
+	if req.Provider != nil {
+		m.Provider = *req.Provider
+	}
+	if req.ModelName != nil {
+		m.ModelName = *req.ModelName
+	}
+	if req.Config != nil {
+		m.Config = *req.Config
+	}
+	if req.Capabilities != nil {
+		m.Capabilities = *req.Capabilities
+	}
+	if req.Status != nil {
+		m.Status = *req.Status
+	}
+	if req.IsActive != nil {
+		m.Status = activeStatus(*req.IsActive)
+	}
+	if req.IsDefault != nil {
+		m.IsDefault = *req.IsDefault
+		if m.IsDefault {
+			s.setDefaultModel(m)
+		}
+	}
+	m.UpdatedAt = time.Now()
+	return *m, nil
+}
+
+func (s *Server) deleteModel(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, m := range s.models {
+		if m.ID == id {
+			s.models = append(s.models[:i:i], s.models[i+1:]...)
+			return nil
+		}
+	}
+	return errorf(http.StatusNotFound, "model %s not found", id)
+}
+
+func (s *Server) listProviderModels(_ *http.Request, body []byte) (interface{}, error) {
+	var req sdk.ListProviderModelsRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Provider == "" {
+		return nil, errorf(http.StatusBadRequest, "provider is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	models := []sdk.ProviderModel{}
+	seen := make(map[string]bool)
+	for _, m := range s.models {
+		if m.Provider != req.Provider || seen[m.ModelName] {
+			continue
+		}
+		seen[m.ModelName] = true
+		models = append(models, sdk.ProviderModel{
+			ID:           m.ModelName,
+			Name:         m.ModelName,
+			Capabilities: []string{m.ModelType},
+		})
+	}
+	return models, nil
+}
+
+func (s *Server) checkModel(_ *http.Request, body []byte) (interface{}, error) {
+	var req sdk.CheckModelRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Provider == "" || req.ModelName == "" {
+		return sdk.CheckModelResponse{Valid: false, Error: "provider and model_name are required"}, nil
+	}
+	return sdk.CheckModelResponse{Valid: true}, nil
+}
+
+func (s *Server) upsertModel(_ *http.Request, body []byte) (interface{}, error) {
+	var req sdk.UpsertModelRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.ModelType == "" || req.Provider == "" || req.ModelName == "" {
+		return nil, errorf(http.StatusBadRequest, "model_type, provider and model_name are required")
+	}
+
+	s.mu.Lock()
+	for _, m := range s.models {
+		if m.Config.APIBase != req.Config.APIBase || m.ModelName != req.ModelName {
+			continue
+		}
+		if req.Name != "" {
+			m.Name = req.Name
+		}
+		if req.Description != "" {
+			m.Description = req.Description
+		}
+		m.ModelType = req.ModelType
+		m.Provider = req.Provider
+		m.Config = req.Config
+		m.Capabilities = req.Capabilities
+		m.Status = activeStatus(req.IsActive)
+		m.IsDefault = req.IsDefault
+		if m.IsDefault {
+			s.setDefaultModel(m)
+		}
+		m.UpdatedAt = time.Now()
+		model := *m
+		s.mu.Unlock()
+		return sdk.UpsertModelResponse{Action: "updated", Model: model}, nil
+	}
+	s.mu.Unlock()
+
+	name := req.Name
+	if name == "" {
+		name = req.ModelName
+	}
+	model := s.AddModel(sdk.AIModel{
+		Name:         name,
+		Description:  req.Description,
+		ModelType:    req.ModelType,
+		Provider:     req.Provider,
+		ModelName:    req.ModelName,
+		Config:       req.Config,
+		Capabilities: req.Capabilities,
+		Status:       activeStatus(req.IsActive),
+		IsDefault:    req.IsDefault,
+	})
+	return sdk.UpsertModelResponse{Action: "created", Model: model}, nil
+}
+
+// findModel 按 ID 查找模型，调用方需持有锁
+func (s *Server) findModel(id string) (*sdk.AIModel, error) {
+	for _, m := range s.models {
+		if m.ID == id {
+			return m, nil
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "model %s not found", id)
+}
+
+// setDefaultModel 取消同类型其他模型的默认标记，调用方需持有锁
+func (s *Server) setDefaultModel(model *sdk.AIModel) {
+	for _, m := range s.models {
+		if m.ID != model.ID && m.ModelType == model.ModelType {
+			m.IsDefault = false
+		}
+	}
+}
+
+func activeStatus(active bool) string {
+	if active {
+		return "active"
+	}
+	return "inactive"
+}
@@ -0,0 +1,33 @@
+package sdktest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// paginate 按 page 和 page_size 参数分页，page_size 为 0 时返回全部
+func paginate[T any](items []T, q url.Values) (page, pageSize int, result []T, err error) {
+	page, pageSize = 1, 20
+	if v := q.Get("page"); v != "" {
+		if page, err = strconv.Atoi(v); err != nil || page < 1 {
+			return 0, 0, nil, errorf(http.StatusBadRequest, "invalid page %q", v)
+		}
+	}
+	if v := q.Get("page_size"); v != "" {
+		if pageSize, err = strconv.Atoi(v); err != nil || pageSize < 0 {
+			return 0, 0, nil, errorf(http.StatusBadRequest, "invalid page_size %q", v)
+		}
+	}
+
+	result = []T{}
+	if pageSize == 0 {
+		return page, pageSize, append(result, items...), nil
+	}
+	start := (page - 1) * pageSize
+	if start >= len(items) {
+		return page, pageSize, result, nil
+	}
+	end := min(start+pageSize, len(items))
+	return page, pageSize, append(result, items[start:end]...), nil
+}
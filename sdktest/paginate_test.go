@@ -0,0 +1,65 @@
+package sdktest
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+func TestPaginate(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		query        string
+		wantPage     int
+		wantPageSize int
+		want         []int
+		wantErr      bool
+	}{
+		{"", 1, 20, []int{1, 2, 3, 4, 5}, false},
+		{"page=1&page_size=2", 1, 2, []int{1, 2}, false},
+		{"page=3&page_size=2", 3, 2, []int{5}, false},
+		{"page=4&page_size=2", 4, 2, []int{}, false},
+		{"page_size=0", 1, 0, []int{1, 2, 3, 4, 5}, false},
+		{"page=0", 0, 0, nil, true},
+		{"page=x", 0, 0, nil, true},
+		{"page_size=-1", 0, 0, nil, true},
+	}
+	for _, tt := range tests {
+		q, _ := url.ParseQuery(tt.query)
+		page, pageSize, got, err := paginate(items, q)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("paginate(%q) error = nil, want an error", tt.query)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("paginate(%q) error = %v", tt.query, err)
+			continue
+		}
+		if page != tt.wantPage || pageSize != tt.wantPageSize || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("paginate(%q) = %d, %d, %v, want %d, %d, %v",
+				tt.query, page, pageSize, got, tt.wantPage, tt.wantPageSize, tt.want)
+		}
+	}
+}
+
+func TestListPagination(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	for _, name := range []string{"a", "b", "c"} {
+		srv.AddDataset(sdk.Dataset{Name: name})
+	}
+	client := srv.Client()
+
+	resp, err := client.Datasets.List(context.Background(), &sdk.ListDatasetsRequest{Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 3 || resp.Page != 2 || resp.PageSize != 2 || len(resp.Datasets) != 1 || resp.Datasets[0].Name != "c" {
+		t.Errorf("page 2 = %+v, want the last dataset with total 3", resp)
+	}
+}
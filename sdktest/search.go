@@ -0,0 +1,364 @@
+package sdktest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// searchScript 预设的搜索结果
+type searchScript struct {
+	query   string
+	results []sdk.SearchResult
+}
+
+// qaScript 预设的问答结果
+type qaScript struct {
+	query  string
+	answer string
+	chunks []sdk.SearchResult
+	err    string
+}
+
+// generateScript 预设的生成结果
+type generateScript struct {
+	query  string
+	answer string
+}
+
+// ScriptSearch 预设 query 的搜索结果，query 为空时匹配所有查询
+//
+// 预设结果优先于按文档内容检索，后添加的预设优先匹配。
+func (s *Server) ScriptSearch(query string, results ...sdk.SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.searchScripts = append(s.searchScripts, searchScript{query: query, results: results})
+}
+
+// ScriptQA 预设 query 的答案和引用的上下文，query 为空时匹配所有问题
+func (s *Server) ScriptQA(query, answer string, context ...sdk.SearchResult) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.qaScripts = append(s.qaScripts, qaScript{query: query, answer: answer, chunks: context})
+}
+
+// ScriptQAError 预设 query 的问答失败，非流式请求返回 500，流式请求返回 error 事件
+func (s *Server) ScriptQAError(query, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.qaScripts = append(s.qaScripts, qaScript{query: query, err: message})
+}
+
+// ScriptGenerate 预设 query 的生成结果，query 为空时匹配所有请求
+func (s *Server) ScriptGenerate(query, answer string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.generateScripts = append(s.generateScripts, generateScript{query: query, answer: answer})
+}
+
+func (s *Server) search(_ *http.Request, body []byte) (interface{}, error) {
+	start := time.Now()
+	var req sdk.RetrieveRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	results, err := s.retrieve(&req)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.SearchResponse{
+		Query:     req.Query,
+		Results:   results,
+		Total:     len(results),
+		LatencyMs: time.Since(start).Milliseconds(),
+	}, nil
+}
+
+func (s *Server) qa(_ *http.Request, body []byte) (interface{}, error) {
+	var req sdk.QARequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	script, ok := matchScript(s.qaScripts, req.Query, func(sc qaScript) string { return sc.query })
+	s.mu.Unlock()
+
+	var resp sdk.QAResponse
+	switch {
+	case ok && script.err != "":
+		if req.Stream {
+			return &eventStream{events: []interface{}{
+				map[string]string{"type": sdk.QAEventError, "error": script.err},
+			}}, nil
+		}
+		return nil, errorf(http.StatusInternalServerError, "%s", script.err)
+	case ok:
+		resp = sdk.QAResponse{Answer: script.answer, Context: script.chunks}
+	default:
+		results, err := s.retrieve(&sdk.RetrieveRequest{
+			Query:               req.Query,
+			DatasetID:           req.DatasetID,
+			TopK:                req.TopK,
+			RetrievalMode:       req.RetrievalMode,
+			SimilarityThreshold: req.SimilarityThreshold,
+			ChatHistory:         req.ChatHistory,
+		})
+		if err != nil {
+			return nil, err
+		}
+		resp = sdk.QAResponse{Answer: "No relevant content found.", Context: results}
+		if len(results) > 0 {
+			resp.Answer = results[0].Content
+		}
+	}
+	if resp.Context == nil {
+		resp.Context = []sdk.SearchResult{}
+	}
+
+	if !req.Stream {
+		return resp, nil
+	}
+	events := []interface{}{map[string]interface{}{"type": sdk.QAEventContext, "context": resp.Context}}
+	for _, delta := range splitDeltas(resp.Answer) {
+		events = append(events, map[string]string{"type": sdk.QAEventAnswer, "content": delta})
+	}
+	events = append(events, map[string]interface{}{"type": sdk.QAEventDone, "answer": resp.Answer, "context": resp.Context})
+	return &eventStream{events: events}, nil
+}
+
+func (s *Server) generate(_ *http.Request, body []byte) (interface{}, error) {
+	var req sdk.GenerateRequest
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.Query == "" {
+		return nil, errorf(http.StatusBadRequest, "query is required")
+	}
+
+	s.mu.Lock()
+	script, ok := matchScript(s.generateScripts, req.Query, func(sc generateScript) string { return sc.query })
+	s.mu.Unlock()
+
+	// 未预设时回显 Context，便于断言传入的上下文
+	answer := req.Context
+	if ok {
+		answer = script.answer
+	} else if answer == "" {
+		answer = req.Query
+	}
+
+	if !req.Stream {
+		return sdk.GenerateResponse{Answer: answer}, nil
+	}
+	prompt := len(tokenize(req.Query)) + len(tokenize(req.Context))
+	completion := len(tokenize(answer))
+	var events []interface{}
+	for _, delta := range splitDeltas(answer) {
+		events = append(events, map[string]string{"type": sdk.GenerateEventDelta, "content": delta})
+	}
+	events = append(events, map[string]interface{}{
+		"type":          sdk.GenerateEventDone,
+		"answer":        answer,
+		"finish_reason": "stop",
+		"usage": sdk.TokenUsage{
+			PromptTokens:     prompt,
+			CompletionTokens: completion,
+			TotalTokens:      prompt + completion,
+		},
+	})
+	return &eventStream{events: events}, nil
+}
+
+// retrieve 返回预设结果，没有预设时在已完成处理的文档分块中按关键词检索
+func (s *Server) retrieve(req *sdk.RetrieveRequest) ([]sdk.SearchResult, error) {
+	if req.Query == "" {
+		return nil, errorf(http.StatusBadRequest, "query is required")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.findDataset(req.DatasetID); err != nil {
+		return nil, err
+	}
+	if script, ok := matchScript(s.searchScripts, req.Query, func(sc searchScript) string { return sc.query }); ok {
+		return append([]sdk.SearchResult{}, script.results...), nil
+	}
+
+	terms := tokenize(req.Query)
+	results := []sdk.SearchResult{}
+	for _, doc := range s.documents[req.DatasetID] {
+		if doc.Status != sdk.DocumentStatusCompleted || !matchDocument(doc, req) {
+			continue
+		}
+		var docResults []sdk.SearchResult
+		for _, c := range doc.chunks {
+			score := scoreChunk(terms, c.content)
+			if score == 0 || score < req.SimilarityThreshold {
+				continue
+			}
+			metadata, _ := doc.Metadata.Data.(map[string]interface{})
+			docResults = append(docResults, sdk.SearchResult{
+				ChunkID:       c.id,
+				DocumentID:    doc.ID,
+				DocumentTitle: doc.Title,
+				SectionTitle:  c.section,
+				Content:       c.content,
+				Score:         score,
+				Metadata:      metadata,
+				Tags:          doc.Tags,
+			})
+		}
+		sort.SliceStable(docResults, func(i, j int) bool { return docResults[i].Score > docResults[j].Score })
+		if req.MaxChunksPerDoc > 0 && len(docResults) > req.MaxChunksPerDoc {
+			docResults = docResults[:req.MaxChunksPerDoc]
+		}
+		results = append(results, docResults...)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	topK := req.TopK
+	if topK <= 0 {
+		topK = 10
+	}
+	if len(results) > topK {
+		results = results[:topK]
+	}
+	return results, nil
+}
+
+// matchDocument 检查文档是否满足标签和 metadata 过滤条件，标签匹配任意一个即可
+func matchDocument(doc *document, req *sdk.RetrieveRequest) bool {
+	if len(req.Tags) > 0 {
+		found := false
+		for _, want := range req.Tags {
+			for _, tag := range doc.Tags {
+				found = found || tag == want
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	metadata, _ := doc.Metadata.Data.(map[string]interface{})
+	for k, want := range req.Metadata {
+		if fmt.Sprint(metadata[k]) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchScript 返回最后添加的匹配预设
+func matchScript[T any](scripts []T, query string, key func(T) string) (T, bool) {
+	for i := len(scripts) - 1; i >= 0; i-- {
+		if q := key(scripts[i]); q == "" || q == query {
+			return scripts[i], true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// tokenize 将文本拆分为小写的词，CJK 文本按相邻两字切分
+func tokenize(text string) []string {
+	var terms []string
+	var word, cjk []rune
+	flush := func() {
+		if len(word) > 0 {
+			terms = append(terms, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+		if len(cjk) == 1 {
+			terms = append(terms, string(cjk))
+		}
+		for i := 0; i+1 < len(cjk); i++ {
+			terms = append(terms, string(cjk[i:i+2]))
+		}
+		cjk = cjk[:0]
+	}
+
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			if len(word) > 0 {
+				terms = append(terms, strings.ToLower(string(word)))
+				word = word[:0]
+			}
+			cjk = append(cjk, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if len(cjk) > 0 {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return terms
+}
+
+// scoreChunk 计算查询词在分块中出现的比例
+func scoreChunk(terms []string, content string) float64 {
+	if len(terms) == 0 {
+		return 0
+	}
+	content = strings.ToLower(content)
+	matched := 0
+	for _, term := range terms {
+		if strings.Contains(content, term) {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(terms))
+}
+
+// splitDeltas 将答案按词切分为流式增量
+func splitDeltas(answer string) []string {
+	var deltas []string
+	var b strings.Builder
+	for _, r := range answer {
+		b.WriteRune(r)
+		if unicode.IsSpace(r) || unicode.Is(unicode.Han, r) || unicode.IsPunct(r) {
+			deltas = append(deltas, b.String())
+			b.Reset()
+		}
+	}
+	if b.Len() > 0 {
+		deltas = append(deltas, b.String())
+	}
+	return deltas
+}
+
+// eventStream 以 SSE 格式返回的流式响应
+type eventStream struct {
+	events []interface{}
+}
+
+func (es *eventStream) write(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	flusher, _ := w.(http.Flusher)
+	for _, ev := range es.events {
+		data, err := json.Marshal(ev)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
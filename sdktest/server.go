@@ -0,0 +1,339 @@
+// Package sdktest 提供用于测试的内存版 RAGLite 服务。
+//
+// Server 基于 httptest.Server 实现了 SDK 调用的全部接口，数据保存在内存中，
+// 响应使用与真实服务相同的 APIResponse 格式。可以注入延迟和错误状态码，
+// 也可以为搜索、问答和生成预设返回结果，用于测试调用方的错误处理逻辑：
+//
+//	srv := sdktest.NewServer()
+//	defer srv.Close()
+//
+//	client := srv.Client()
+//	srv.InjectFault(sdktest.Fault{Path: "/api/v1/search", StatusCode: 503, Times: 1})
+//	srv.ScriptSearch("RAGLite", sdk.SearchResult{Content: "RAGLite 是一个 RAG 服务"})
+package sdktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// Server 内存版 RAGLite 服务
+type Server struct {
+	// URL 服务地址，形如 http://127.0.0.1:port
+	URL string
+
+	srv             *httptest.Server
+	apiKey          string
+	processingSteps int
+	processor       func(doc sdk.Document, content []byte) error
+
+	mu              sync.Mutex
+	nextID          int
+	models          []*sdk.AIModel
+	datasets        []*sdk.Dataset
+	documents       map[string][]*document
+	faults          []*faultState
+	searchScripts   []searchScript
+	qaScripts       []qaScript
+	generateScripts []generateScript
+	requests        []Request
+}
+
+// Option 服务配置选项
+type Option func(*Server)
+
+// WithAPIKey 要求请求携带 Authorization: Bearer <apiKey>，否则返回 401
+func WithAPIKey(apiKey string) Option {
+	return func(s *Server) {
+		s.apiKey = apiKey
+	}
+}
+
+// WithProcessingSteps 设置文档处理的快慢
+//
+// 上传后的文档处于 pending 状态，之后每被查询一次（Get 或 List）推进一步：
+// 先进入 processing 并停留 steps 次查询，然后变为 completed 或 failed。
+// steps 为 0 时文档上传后立即完成，默认为 1。
+func WithProcessingSteps(steps int) Option {
+	return func(s *Server) {
+		s.processingSteps = steps
+	}
+}
+
+// WithDocumentProcessor 设置文档处理函数，返回错误时文档最终状态为 failed，
+// ProgressMsg 为错误信息，可用于模拟解析失败
+func WithDocumentProcessor(fn func(doc sdk.Document, content []byte) error) Option {
+	return func(s *Server) {
+		s.processor = fn
+	}
+}
+
+// Request 服务收到的请求
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// NewServer 创建并启动服务，使用完毕后需要调用 Close
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		processingSteps: 1,
+		documents:       make(map[string][]*document),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.srv = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close 关闭服务
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client 创建连接到该服务的 SDK 客户端，opts 会追加在默认选项之后
+func (s *Server) Client(opts ...sdk.Option) *sdk.Client {
+	defaults := []sdk.Option{sdk.WithHTTPClient(s.srv.Client())}
+	if s.apiKey != "" {
+		defaults = append(defaults, sdk.WithAPIKey(s.apiKey))
+	}
+
+	client, err := sdk.NewClient(s.URL, append(defaults, opts...)...)
+	if err != nil {
+		panic(fmt.Sprintf("sdktest: failed to create client: %v", err))
+	}
+	return client
+}
+
+// Requests 返回服务收到的全部请求，包括被注入错误的请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset 清空所有数据、预设结果、错误注入和请求记录
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID = 0
+	s.models = nil
+	s.datasets = nil
+	s.documents = make(map[string][]*document)
+	s.faults = nil
+	s.searchScripts = nil
+	s.qaScripts = nil
+	s.generateScripts = nil
+	s.requests = nil
+}
+
+// httpError 处理函数返回的错误，转换为失败的 APIResponse
+type httpError struct {
+	status  int
+	message string
+}
+
+func (e *httpError) Error() string {
+	return e.message
+}
+
+func errorf(status int, format string, args ...interface{}) *httpError {
+	return &httpError{status: status, message: fmt.Sprintf(format, args...)}
+}
+
+// handler 路由处理函数，返回值作为 APIResponse 的 data
+type handler func(r *http.Request, body []byte) (interface{}, error)
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, errorf(http.StatusBadRequest, "failed to read request body: %v", err))
+		return
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+	fault := s.matchFault(r.Method, r.URL.Path)
+	s.mu.Unlock()
+
+	if fault != nil && !fault.apply(w, r) {
+		return
+	}
+
+	if s.apiKey != "" && r.Header.Get("Authorization") != "Bearer "+s.apiKey {
+		writeError(w, errorf(http.StatusUnauthorized, "invalid api key"))
+		return
+	}
+
+	h, err := s.route(r.Method, r.URL.Path)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	data, err := h(r, body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if stream, ok := data.(*eventStream); ok {
+		stream.write(w)
+		return
+	}
+	writeJSON(w, http.StatusOK, sdk.APIResponse{Success: true, Data: data})
+}
+
+// routes 按方法区分的处理函数
+type routes map[string]handler
+
+func (rt routes) match(method string) (handler, error) {
+	if h, ok := rt[method]; ok {
+		return h, nil
+	}
+	return nil, errorf(http.StatusMethodNotAllowed, "method %s not allowed", method)
+}
+
+// route 按方法和路径查找处理函数
+func (s *Server) route(method, path string) (handler, error) {
+	if path == "/health" {
+		return routes{"GET": s.health}.match(method)
+	}
+	if !strings.HasPrefix(path, "/api/v1/") {
+		return nil, errorf(http.StatusNotFound, "route %s not found", path)
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(path, "/api/v1/"), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "search":
+		return routes{"POST": s.search}.match(method)
+	case len(parts) == 1 && parts[0] == "qa":
+		return routes{"POST": s.qa}.match(method)
+	case len(parts) == 1 && parts[0] == "generate":
+		return routes{"POST": s.generate}.match(method)
+
+	case parts[0] == "models":
+		switch {
+		case len(parts) == 1:
+			return routes{"GET": s.listModels, "POST": s.createModel}.match(method)
+		case len(parts) == 2 && parts[1] == "check" && method == "POST":
+			return s.checkModel, nil
+		case len(parts) == 2 && parts[1] == "upsert" && method == "POST":
+			return s.upsertModel, nil
+		case len(parts) == 3 && parts[1] == "provider" && parts[2] == "supported":
+			return routes{"POST": s.listProviderModels}.match(method)
+		case len(parts) == 2:
+			id := parts[1]
+			return routes{
+				"GET":    func(*http.Request, []byte) (interface{}, error) { return s.getModel(id) },
+				"PUT":    func(_ *http.Request, body []byte) (interface{}, error) { return s.updateModel(id, body) },
+				"DELETE": func(*http.Request, []byte) (interface{}, error) { return nil, s.deleteModel(id) },
+			}.match(method)
+		}
+
+	case parts[0] == "datasets":
+		switch {
+		case len(parts) == 1:
+			return routes{"GET": s.listDatasets, "POST": s.createDataset}.match(method)
+		case len(parts) == 2:
+			id := parts[1]
+			return routes{
+				"GET":    func(*http.Request, []byte) (interface{}, error) { return s.getDataset(id) },
+				"PUT":    func(_ *http.Request, body []byte) (interface{}, error) { return s.updateDataset(id, body) },
+				"DELETE": func(*http.Request, []byte) (interface{}, error) { return nil, s.deleteDataset(id) },
+			}.match(method)
+		case len(parts) == 3 && parts[2] == "stats":
+			id := parts[1]
+			return routes{
+				"GET": func(*http.Request, []byte) (interface{}, error) { return s.datasetStats(id) },
+			}.match(method)
+		case len(parts) >= 3 && parts[2] == "documents":
+			return s.routeDocuments(method, parts[1], parts[3:])
+		}
+	}
+	return nil, errorf(http.StatusNotFound, "route %s not found", path)
+}
+
+// routeDocuments 路由 /api/v1/datasets/{id}/documents 下的请求
+func (s *Server) routeDocuments(method, datasetID string, parts []string) (handler, error) {
+	switch {
+	case len(parts) == 0:
+		return routes{
+			"GET":  func(r *http.Request, _ []byte) (interface{}, error) { return s.listDocuments(datasetID, r.URL.Query()) },
+			"POST": func(r *http.Request, _ []byte) (interface{}, error) { return s.uploadDocument(datasetID, r) },
+		}.match(method)
+	case len(parts) == 1 && parts[0] == "batch-delete" && method == "POST":
+		return func(_ *http.Request, body []byte) (interface{}, error) {
+			return nil, s.batchDeleteDocuments(datasetID, body)
+		}, nil
+	case len(parts) == 1:
+		id := parts[0]
+		return routes{
+			"GET":    func(*http.Request, []byte) (interface{}, error) { return s.getDocument(datasetID, id) },
+			"PATCH":  func(_ *http.Request, body []byte) (interface{}, error) { return s.updateDocument(datasetID, id, body) },
+			"DELETE": func(*http.Request, []byte) (interface{}, error) { return nil, s.deleteDocument(datasetID, id) },
+		}.match(method)
+	case len(parts) == 2 && parts[1] == "reindex":
+		id := parts[0]
+		return routes{
+			"POST": func(*http.Request, []byte) (interface{}, error) { return s.reindexDocument(datasetID, id) },
+		}.match(method)
+	}
+	return nil, errorf(http.StatusNotFound, "route not found")
+}
+
+func (s *Server) health(*http.Request, []byte) (interface{}, error) {
+	return sdk.HealthResponse{Status: "ok", Service: "raglite"}, nil
+}
+
+// newID 生成带前缀的递增 ID，调用方需持有锁
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s-%d", prefix, s.nextID)
+}
+
+// decode 解析 JSON 请求体
+func decode(body []byte, v interface{}) error {
+	if len(body) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return errorf(http.StatusBadRequest, "invalid request body: %v", err)
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if e, ok := err.(*httpError); ok {
+		status = e.status
+	}
+	writeJSON(w, status, sdk.APIResponse{Success: false, Message: err.Error()})
+}
@@ -0,0 +1,283 @@
+package sdktest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	sdk "github.com/chaitin/raglite-go-sdk"
+)
+
+// rawResponse 直接发送的请求的响应
+type rawResponse struct {
+	status int
+	header http.Header
+	body   sdk.APIResponse
+}
+
+// rawCall 不经过 SDK 直接请求服务，用于检查路由和认证
+func rawCall(t *testing.T, srv *Server, method, path, body string, header http.Header) rawResponse {
+	t.Helper()
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out rawResponse
+	out.status = resp.StatusCode
+	out.header = resp.Header
+	if err := json.Unmarshal(data, &out.body); err != nil {
+		t.Fatalf("%s %s: response is not an APIResponse envelope: %s", method, path, data)
+	}
+	return out
+}
+
+func TestRoutes(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddDataset(sdk.Dataset{ID: "ds-1", Name: "docs"})
+	srv.AddModel(sdk.AIModel{ID: "m-1", Name: "embed", ModelType: "embedding", Provider: "openai", ModelName: "text-embedding-3-small"})
+
+	tests := []struct {
+		method, path, body string
+		wantStatus         int
+	}{
+		{"GET", "/health", "", http.StatusOK},
+		{"POST", "/health", "", http.StatusMethodNotAllowed},
+		{"GET", "/unknown", "", http.StatusNotFound},
+		{"GET", "/api/v1/unknown", "", http.StatusNotFound},
+
+		{"GET", "/api/v1/models", "", http.StatusOK},
+		{"GET", "/api/v1/models/m-1", "", http.StatusOK},
+		{"GET", "/api/v1/models/missing", "", http.StatusNotFound},
+		{"PATCH", "/api/v1/models/m-1", "{}", http.StatusMethodNotAllowed},
+		{"POST", "/api/v1/models", `{"name":""}`, http.StatusBadRequest},
+
+		{"GET", "/api/v1/datasets", "", http.StatusOK},
+		{"GET", "/api/v1/datasets/ds-1", "", http.StatusOK},
+		{"GET", "/api/v1/datasets/ds-1/stats", "", http.StatusOK},
+		{"GET", "/api/v1/datasets/missing", "", http.StatusNotFound},
+		{"POST", "/api/v1/datasets", `{"name":"other"}`, http.StatusOK},
+		{"POST", "/api/v1/datasets", `{`, http.StatusBadRequest},
+
+		{"GET", "/api/v1/datasets/ds-1/documents", "", http.StatusOK},
+		{"GET", "/api/v1/datasets/missing/documents", "", http.StatusNotFound},
+		{"GET", "/api/v1/datasets/ds-1/documents/missing", "", http.StatusNotFound},
+		{"POST", "/api/v1/datasets/ds-1/documents/batch-delete", `{"document_ids":[]}`, http.StatusBadRequest},
+		{"GET", "/api/v1/datasets/ds-1/documents/a/b/c", "", http.StatusNotFound},
+
+		{"POST", "/api/v1/search", `{"dataset_id":"ds-1","query":"q"}`, http.StatusOK},
+		{"POST", "/api/v1/search", `{"dataset_id":"ds-1"}`, http.StatusBadRequest},
+		{"GET", "/api/v1/search", "", http.StatusMethodNotAllowed},
+		{"POST", "/api/v1/qa", `{"dataset_id":"ds-1","query":"q"}`, http.StatusOK},
+		{"POST", "/api/v1/generate", `{"query":"q"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp := rawCall(t, srv, tt.method, tt.path, tt.body, nil)
+			if resp.status != tt.wantStatus {
+				t.Errorf("status = %d, want %d (message %q)", resp.status, tt.wantStatus, resp.body.Message)
+			}
+			if wantSuccess := tt.wantStatus == http.StatusOK; resp.body.Success != wantSuccess {
+				t.Errorf("success = %v, want %v", resp.body.Success, wantSuccess)
+			}
+			if tt.wantStatus != http.StatusOK && resp.body.Message == "" {
+				t.Error("error response has no message")
+			}
+		})
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	srv := NewServer(WithAPIKey("k"))
+	defer srv.Close()
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantStatus    int
+	}{
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong key", "Bearer other", http.StatusUnauthorized},
+		{"not a bearer token", "k", http.StatusUnauthorized},
+		{"valid", "Bearer k", http.StatusOK},
+	}
+	for _, tt := range tests {
+		for _, path := range []string{"/health", "/api/v1/datasets"} {
+			header := http.Header{}
+			if tt.authorization != "" {
+				header.Set("Authorization", tt.authorization)
+			}
+			if resp := rawCall(t, srv, "GET", path, "", header); resp.status != tt.wantStatus {
+				t.Errorf("%s: GET %s status = %d, want %d", tt.name, path, resp.status, tt.wantStatus)
+			}
+		}
+	}
+
+	// Client 自动携带 API Key
+	if _, err := srv.Client().Health.Check(context.Background()); err != nil {
+		t.Errorf("Client().Health.Check: %v", err)
+	}
+	if _, err := srv.Client(sdk.WithAPIKey("other")).Health.Check(context.Background()); !errors.Is(err, sdk.ErrUnauthorized) {
+		t.Errorf("Health.Check with a wrong key error = %v, want ErrUnauthorized", err)
+	}
+}
+
+func TestDocumentLifecycle(t *testing.T) {
+	srv := NewServer(WithProcessingSteps(2))
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	ds, err := client.Datasets.Create(ctx, &sdk.CreateDatasetRequest{Name: "docs"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	up, err := client.Documents.Upload(ctx, &sdk.UploadDocumentRequest{
+		DatasetID: ds.ID,
+		Filename:  "intro.md",
+		File:      strings.NewReader("# Intro\nRAGLite is a retrieval service.\n\nIt supports hybrid search."),
+		Tags:      []string{"guide"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if up.Status != sdk.DocumentStatusPending {
+		t.Errorf("upload status = %q, want pending", up.Status)
+	}
+
+	// 每次查询推进一步：processing 停留 2 次查询后完成
+	want := []string{
+		sdk.DocumentStatusProcessing,
+		sdk.DocumentStatusProcessing,
+		sdk.DocumentStatusCompleted,
+		sdk.DocumentStatusCompleted,
+	}
+	for i, status := range want {
+		doc, err := client.Documents.Get(ctx, ds.ID, up.DocumentID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if doc.Status != status {
+			t.Errorf("query %d: status = %q, want %q", i+1, doc.Status, status)
+		}
+	}
+
+	// 完成后的文档可以被检索到，章节标题来自 # 行
+	resp, err := client.Search.Retrieve(ctx, &sdk.RetrieveRequest{DatasetID: ds.ID, Query: "hybrid search"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) == 0 || resp.Results[0].SectionTitle != "Intro" || !strings.Contains(resp.Results[0].Content, "hybrid") {
+		t.Errorf("results = %+v, want the hybrid search chunk in section Intro", resp.Results)
+	}
+
+	if err := client.Documents.Delete(ctx, ds.ID, up.DocumentID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Documents.Get(ctx, ds.ID, up.DocumentID); !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("Get after Delete error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestDocumentProcessorFailure(t *testing.T) {
+	srv := NewServer(WithProcessingSteps(0), WithDocumentProcessor(func(doc sdk.Document, content []byte) error {
+		return errors.New("unsupported format")
+	}))
+	defer srv.Close()
+	srv.AddDataset(sdk.Dataset{ID: "ds-1", Name: "docs"})
+	client := srv.Client()
+
+	up, err := client.Documents.Upload(context.Background(), &sdk.UploadDocumentRequest{
+		DatasetID: "ds-1", Filename: "a.bin", File: strings.NewReader("x"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, err := client.Documents.Get(context.Background(), "ds-1", up.DocumentID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Status != sdk.DocumentStatusFailed || doc.ProgressMsg != "unsupported format" {
+		t.Errorf("document = %q %q, want failed with the processor error", doc.Status, doc.ProgressMsg)
+	}
+}
+
+func TestScriptedQAStream(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.ScriptQA("what is raglite", "RAGLite is a RAG service.", sdk.SearchResult{ChunkID: "c1"})
+	srv.ScriptQAError("fail", "model unavailable")
+	client := srv.Client()
+	ctx := context.Background()
+
+	stream, err := client.QA.AskStream(ctx, &sdk.QARequest{DatasetID: "ds-1", Query: "what is raglite"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var answer string
+	var done bool
+	for stream.Next() {
+		ev := stream.Current()
+		switch ev.Type {
+		case sdk.QAEventAnswer:
+			answer += ev.Delta
+		case sdk.QAEventDone:
+			done = ev.Answer == "RAGLite is a RAG service." && len(ev.Context) == 1
+		}
+	}
+	stream.Close()
+	if err := stream.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if answer != "RAGLite is a RAG service." || !done {
+		t.Errorf("streamed answer = %q, done = %v, want the scripted answer and a done event", answer, done)
+	}
+
+	if _, err := client.QA.Ask(ctx, &sdk.QARequest{DatasetID: "ds-1", Query: "fail"}); err == nil ||
+		!strings.Contains(err.Error(), "model unavailable") {
+		t.Errorf("Ask error = %v, want the scripted error", err)
+	}
+}
+
+func TestRequestsAndReset(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.AddDataset(sdk.Dataset{ID: "ds-1", Name: "docs"})
+	client := srv.Client()
+
+	if _, err := client.Datasets.Get(context.Background(), "ds-1", sdk.WithHeader("X-Tenant", "acme")); err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 1 || reqs[0].Method != "GET" || reqs[0].Path != "/api/v1/datasets/ds-1" || reqs[0].Header.Get("X-Tenant") != "acme" {
+		t.Fatalf("requests = %+v, want the recorded GET", reqs)
+	}
+
+	srv.Reset()
+	if got := srv.Requests(); len(got) != 0 {
+		t.Errorf("requests after Reset = %d, want 0", len(got))
+	}
+	if _, err := client.Datasets.Get(context.Background(), "ds-1"); !errors.Is(err, sdk.ErrNotFound) {
+		t.Errorf("Get after Reset error = %v, want ErrNotFound", err)
+	}
+}
@@ -7,6 +7,12 @@ type SearchService struct {
 	client *Client
 }
 
+// 检索模式
+const (
+	RetrievalModeFull  = "full"
+	RetrievalModeSmart = "smart"
+)
+
 // RetrieveRequest 召回请求
 type RetrieveRequest struct {
 	Query               string                 `json:"query"`
@@ -20,6 +26,19 @@ type RetrieveRequest struct {
 	MaxChunksPerDoc     int                    `json:"max_chunks_per_doc,omitempty"`
 }
 
+// Validate 校验请求参数
+func (r *RetrieveRequest) Validate() error {
+	if r == nil {
+		return errNilRequest()
+	}
+	var v validation
+	v.required("query", r.Query)
+	v.required("dataset_id", r.DatasetID)
+	v.retrieval(r.TopK, r.RetrievalMode, r.SimilarityThreshold, r.ChatHistory)
+	v.nonNegative("max_chunks_per_doc", r.MaxChunksPerDoc)
+	return v.err()
+}
+
 // SearchResponse 搜索响应
 type SearchResponse struct {
 	Query     string         `json:"query"`
@@ -29,14 +48,19 @@ type SearchResponse struct {
 }
 
 // Search 执行搜索
-func (s *SearchService) Retrieve(ctx context.Context, req *RetrieveRequest) (*SearchResponse, error) {
+func (s *SearchService) Retrieve(ctx context.Context, req *RetrieveRequest, opts ...CallOption) (*SearchResponse, error) {
+	if err := s.client.validate(req); err != nil {
+		return nil, err
+	}
+
 	// 设置默认值
 	if req.TopK <= 0 {
 		req.TopK = 10
 	}
 
 	var result SearchResponse
-	err := s.client.do(ctx, "POST", "/api/v1/search", req, &result)
+	op := &Operation{Service: "Search", Method: "Retrieve", HTTPMethod: "POST", Path: "/api/v1/search", DatasetID: req.DatasetID, Request: req}
+	err := s.client.do(ctx, op, req, &result, opts...)
 	if err != nil {
 		return nil, err
 	}
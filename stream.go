@@ -0,0 +1,438 @@
+package sdk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxStreamLineSize 流式响应中单行的最大长度
+const maxStreamLineSize = 16 << 20
+
+// StreamError 服务端在流中返回的错误事件
+type StreamError struct {
+	Message string
+}
+
+// Error 实现 error 接口
+func (e *StreamError) Error() string {
+	return fmt.Sprintf("stream error: %s", e.Message)
+}
+
+// Stream 流式响应迭代器，用法与 bufio.Scanner 类似：
+//
+//	stream, err := client.QA.AskStream(ctx, req)
+//	if err != nil {
+//		return err
+//	}
+//	defer stream.Close()
+//	for stream.Next() {
+//		event := stream.Current()
+//		// ...
+//	}
+//	if err := stream.Err(); err != nil {
+//		return err
+//	}
+//
+// 取消创建流时传入的 context 会中断读取，Stream 不是并发安全的。
+type Stream[T any] struct {
+	ctx     context.Context
+	body    io.ReadCloser
+	reader  *eventReader
+	decoder streamDecoder[T]
+
+	pending []T
+	current T
+	err     error
+	done    bool
+}
+
+// streamDecoder 将原始事件转换为类型化事件
+type streamDecoder[T any] interface {
+	// decode 转换一个原始事件，可以返回零个或多个事件
+	decode(ev *streamEvent) ([]T, error)
+
+	// finish 在流结束时调用，用于补发汇总事件；terminated 表示流以 [DONE] 等结束标记正常结束，
+	// 否则流可能被截断，尚未收到结束事件时返回 io.ErrUnexpectedEOF
+	finish(terminated bool) ([]T, error)
+}
+
+// openStream 经过中间件发送流式请求，返回未读取的响应
+func (c *Client) openStream(ctx context.Context, op *Operation, body interface{}, opts ...CallOption) (*http.Response, error) {
+	op.Stream = true
+	op.SetHeader("Accept", "text/event-stream, application/x-ndjson")
+	ctx, cancel := c.prepare(ctx, op, opts)
+
+	var resp *http.Response
+	err := c.invoke(ctx, op, func(ctx context.Context, op *Operation) error {
+		var err error
+		resp, err = c.sendJSON(ctx, op, body)
+		return err
+	})
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		cancel()
+		return nil, err
+	}
+	if resp == nil {
+		cancel()
+		return nil, fmt.Errorf("stream %s was not opened by middleware", op.FullMethod())
+	}
+	// 单次调用的超时覆盖整个流，Stream 关闭时释放
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: cancel}
+	return resp, nil
+}
+
+// newStream 基于 HTTP 响应创建流
+func newStream[T any](ctx context.Context, resp *http.Response, decoder streamDecoder[T]) *Stream[T] {
+	return &Stream[T]{
+		ctx:     ctx,
+		body:    resp.Body,
+		reader:  newEventReader(resp.Body, resp.Header.Get("Content-Type")),
+		decoder: decoder,
+	}
+}
+
+// NewEventStream 创建依次返回 events 的流，读完后 Err 返回 err，用于在测试中构造 Stream
+func NewEventStream[T any](events []T, err error) *Stream[T] {
+	return &Stream[T]{
+		ctx:     context.Background(),
+		body:    io.NopCloser(bytes.NewReader(nil)),
+		pending: append([]T(nil), events...),
+		err:     err,
+		done:    true,
+	}
+}
+
+// Next 读取下一个事件，流结束或出错时返回 false
+func (s *Stream[T]) Next() bool {
+	for len(s.pending) == 0 {
+		if s.done {
+			return false
+		}
+
+		ev, err := s.reader.next()
+		if err != nil {
+			s.done = true
+			if errors.Is(err, io.EOF) {
+				s.pending, err = s.decoder.finish(s.reader.terminated)
+				if err != nil {
+					s.err = fmt.Errorf("failed to read stream: %w", err)
+				}
+				continue
+			}
+			if ctxErr := s.ctx.Err(); ctxErr != nil {
+				err = ctxErr
+			}
+			s.err = fmt.Errorf("failed to read stream: %w", err)
+			return false
+		}
+
+		events, err := s.decoder.decode(ev)
+		s.pending = events
+		if err != nil {
+			s.done = true
+			s.err = err
+		}
+	}
+
+	s.current = s.pending[0]
+	s.pending = s.pending[1:]
+	return true
+}
+
+// Current 返回当前事件
+func (s *Stream[T]) Current() T {
+	return s.current
+}
+
+// Err 返回迭代过程中遇到的错误，正常结束时返回 nil
+func (s *Stream[T]) Err() error {
+	return s.err
+}
+
+// Close 关闭流并释放连接
+func (s *Stream[T]) Close() error {
+	s.done = true
+	return s.body.Close()
+}
+
+// textPayload 问答和生成流共用的事件字段
+type textPayload struct {
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	Delta   string `json:"delta"`
+	Answer  string `json:"answer"`
+	Error   string `json:"error"`
+
+	// 服务端未使用流式输出时返回的 APIResponse
+	Success *bool           `json:"success"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// textEvents 将文本流的通用事件映射为各服务的事件类型，并处理服务特有的字段
+type textEvents[T any] interface {
+	// extra 在处理每个 JSON 事件之前调用，done 表示该事件是结束事件；
+	// handled 为 true 时直接发出返回的事件（例如问答的检索结果），不再作为文本增量处理
+	extra(typ string, done bool, data []byte) (events []T, handled bool)
+
+	// response 处理服务端未使用流式输出时 APIResponse 中的 data，
+	// 返回完整答案和在结束事件之前发出的事件
+	response(data []byte) (answer string, events []T, err error)
+
+	deltaEvent(delta string) T
+	doneEvent(answer string) T
+	errorEvent(msg string) T
+}
+
+// textStreamDecoder 问答和生成共用的流解码器：解析 APIResponse 信封、结束和错误事件，
+// 并累积文本增量用于结束汇总
+type textStreamDecoder[T any] struct {
+	events textEvents[T]
+	answer strings.Builder
+	done   bool
+}
+
+func newTextStreamDecoder[T any](events textEvents[T]) *textStreamDecoder[T] {
+	return &textStreamDecoder[T]{events: events}
+}
+
+func (d *textStreamDecoder[T]) decode(ev *streamEvent) ([]T, error) {
+	var p textPayload
+	data := ev.Data
+	if err := json.Unmarshal(ev.Data, &p); err != nil {
+		// 非 JSON 数据视为纯文本增量
+		p = textPayload{Content: string(ev.Data)}
+		data = nil
+	}
+
+	if p.Success != nil {
+		if !*p.Success {
+			return d.fail(p.Message)
+		}
+		var events []T
+		if len(p.Data) > 0 && string(p.Data) != "null" {
+			answer, evs, err := d.events.response(p.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode stream response: %w", err)
+			}
+			d.answer.WriteString(answer)
+			events = evs
+		}
+		return append(events, d.summary()...), nil
+	}
+
+	typ := ev.Event
+	if typ == "" || typ == "message" {
+		typ = p.Type
+	}
+	done := isTextDoneEvent(typ)
+
+	var events []T
+	if data != nil {
+		var handled bool
+		events, handled = d.events.extra(typ, done, data)
+		if handled {
+			return events, nil
+		}
+	}
+
+	switch {
+	case done:
+		if p.Answer != "" {
+			d.answer.Reset()
+			d.answer.WriteString(p.Answer)
+		}
+		return append(events, d.summary()...), nil
+	case typ == "error":
+		msg := p.Error
+		if msg == "" {
+			msg = p.Message
+		}
+		if msg == "" {
+			msg = p.Content
+		}
+		return d.fail(msg)
+	default:
+		delta := p.Delta
+		if delta == "" {
+			delta = p.Content
+		}
+		if delta == "" {
+			return events, nil
+		}
+		d.answer.WriteString(delta)
+		return append(events, d.events.deltaEvent(delta)), nil
+	}
+}
+
+// isTextDoneEvent 判断是否为结束事件，兼容不同服务端的事件名
+func isTextDoneEvent(typ string) bool {
+	switch typ {
+	case "done", "end", "finish", "summary":
+		return true
+	}
+	return false
+}
+
+// fail 生成错误事件，流随后以 *StreamError 结束
+func (d *textStreamDecoder[T]) fail(msg string) ([]T, error) {
+	d.done = true
+	return []T{d.events.errorEvent(msg)}, &StreamError{Message: msg}
+}
+
+func (d *textStreamDecoder[T]) finish(terminated bool) ([]T, error) {
+	if !d.done && !terminated {
+		d.done = true
+		return nil, io.ErrUnexpectedEOF
+	}
+	return d.summary(), nil
+}
+
+// summary 生成结束事件，只生成一次
+func (d *textStreamDecoder[T]) summary() []T {
+	if d.done {
+		return nil
+	}
+	d.done = true
+	return []T{d.events.doneEvent(d.answer.String())}
+}
+
+// streamEvent 流中的原始事件
+type streamEvent struct {
+	// SSE 的 event 字段，NDJSON 时为空
+	Event string
+	Data  []byte
+}
+
+// eventReader 解析 Server-Sent Events 或 NDJSON 格式的响应体
+type eventReader struct {
+	r       io.Reader
+	scanner *bufio.Scanner
+	format  string
+
+	// terminated 表示读到了结束标记：SSE 的 data: [DONE] 或完整的 JSON 响应体
+	terminated bool
+}
+
+// 流的格式
+const (
+	formatSSE    = "sse"
+	formatNDJSON = "ndjson"
+	formatJSON   = "json"
+)
+
+// newEventReader 根据 Content-Type 选择解析格式：
+// text/event-stream 按 SSE 解析；application/json 表示服务端未使用流式输出，整个响应体作为一个事件；
+// 其他按 NDJSON 解析
+func newEventReader(r io.Reader, contentType string) *eventReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxStreamLineSize)
+
+	format := formatNDJSON
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "text/event-stream":
+		format = formatSSE
+	case "application/json":
+		format = formatJSON
+	}
+
+	return &eventReader{
+		r:       r,
+		scanner: scanner,
+		format:  format,
+	}
+}
+
+// next 读取下一个事件，流结束时返回 io.EOF
+func (r *eventReader) next() (*streamEvent, error) {
+	switch r.format {
+	case formatSSE:
+		return r.nextSSE()
+	case formatJSON:
+		if r.r == nil {
+			return nil, io.EOF
+		}
+		data, err := io.ReadAll(r.r)
+		r.r = nil
+		if err != nil {
+			return nil, err
+		}
+		r.terminated = true
+		return &streamEvent{Data: data}, nil
+	}
+
+	for r.scanner.Scan() {
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		return &streamEvent{Data: append([]byte(nil), line...)}, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// nextSSE 按 SSE 规范读取下一个事件，忽略注释、id 和 retry 字段
+func (r *eventReader) nextSSE() (*streamEvent, error) {
+	var (
+		ev      streamEvent
+		data    [][]byte
+		hasData bool
+	)
+
+	for r.scanner.Scan() {
+		line := r.scanner.Bytes()
+		if len(line) == 0 {
+			if !hasData {
+				ev.Event = ""
+				continue
+			}
+			ev.Data = bytes.Join(data, []byte("\n"))
+			if string(ev.Data) == "[DONE]" {
+				r.terminated = true
+				return nil, io.EOF
+			}
+			return &ev, nil
+		}
+		if line[0] == ':' {
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			ev.Event = string(value)
+		case "data":
+			data = append(data, append([]byte(nil), value...))
+			hasData = true
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// 最后一个事件没有以空行结尾
+	if hasData {
+		ev.Data = bytes.Join(data, []byte("\n"))
+		if string(ev.Data) != "[DONE]" {
+			return &ev, nil
+		}
+		r.terminated = true
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,286 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// readEvents 读取全部原始事件
+func readEvents(t *testing.T, contentType, body string) []streamEvent {
+	t.Helper()
+	r := newEventReader(strings.NewReader(body), contentType)
+	var events []streamEvent
+	for {
+		ev, err := r.next()
+		if errors.Is(err, io.EOF) {
+			return events
+		}
+		if err != nil {
+			t.Fatalf("next: %v", err)
+		}
+		events = append(events, *ev)
+	}
+}
+
+func TestEventReaderSSE(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []streamEvent
+	}{
+		{
+			name: "single line data",
+			body: "data: hello\n\n",
+			want: []streamEvent{{Data: []byte("hello")}},
+		},
+		{
+			name: "multi-line data joined with newline",
+			body: "data: line one\ndata: line two\ndata:\n\n",
+			want: []streamEvent{{Data: []byte("line one\nline two\n")}},
+		},
+		{
+			name: "event name",
+			body: "event: context\ndata: {}\n\ndata: next\n\n",
+			want: []streamEvent{{Event: "context", Data: []byte("{}")}, {Data: []byte("next")}},
+		},
+		{
+			name: "comments id and retry are ignored",
+			body: ": keep-alive\nid: 1\nretry: 1000\ndata: a\n\n: ping\n\n",
+			want: []streamEvent{{Data: []byte("a")}},
+		},
+		{
+			name: "event without data is dropped",
+			body: "event: ping\n\ndata: a\n\n",
+			want: []streamEvent{{Data: []byte("a")}},
+		},
+		{
+			name: "missing trailing blank line",
+			body: "data: a\n\ndata: b",
+			want: []streamEvent{{Data: []byte("a")}, {Data: []byte("b")}},
+		},
+		{
+			name: "CRLF line endings",
+			body: "data: a\r\n\r\ndata: b\r\n\r\n",
+			want: []streamEvent{{Data: []byte("a")}, {Data: []byte("b")}},
+		},
+		{
+			name: "no space after colon",
+			body: "data:a\n\n",
+			want: []streamEvent{{Data: []byte("a")}},
+		},
+		{
+			name: "DONE ends the stream",
+			body: "data: a\n\ndata: [DONE]\n\ndata: b\n\n",
+			want: []streamEvent{{Data: []byte("a")}},
+		},
+		{
+			name: "DONE without trailing blank line",
+			body: "data: a\n\ndata: [DONE]",
+			want: []streamEvent{{Data: []byte("a")}},
+		},
+		{
+			name: "empty body",
+			body: "",
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := readEvents(t, "text/event-stream; charset=utf-8", tt.body)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventReaderNDJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"one object per line", "{\"a\":1}\n{\"b\":2}\n", []string{`{"a":1}`, `{"b":2}`}},
+		{"blank lines and whitespace", "\n  {\"a\":1}  \n\n\t\n{\"b\":2}\n", []string{`{"a":1}`, `{"b":2}`}},
+		{"missing trailing newline", "{\"a\":1}\n{\"b\":2}", []string{`{"a":1}`, `{"b":2}`}},
+		{"CRLF line endings", "{\"a\":1}\r\n{\"b\":2}\r\n", []string{`{"a":1}`, `{"b":2}`}},
+		{"empty body", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ev := range readEvents(t, "application/x-ndjson", tt.body) {
+				got = append(got, string(ev.Data))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventReaderJSONBodyIsOneEvent(t *testing.T) {
+	body := "{\"success\":true,\n\"data\":{}}\n"
+	got := readEvents(t, "application/json", body)
+	if len(got) != 1 || string(got[0].Data) != body {
+		t.Errorf("events = %q, want the whole body as one event", got)
+	}
+}
+
+func TestEventReaderLineTooLong(t *testing.T) {
+	body := "data: " + strings.Repeat("x", maxStreamLineSize+1) + "\n\n"
+	r := newEventReader(strings.NewReader(body), "text/event-stream")
+	if _, err := r.next(); err == nil || errors.Is(err, io.EOF) {
+		t.Errorf("next() error = %v, want a line length error", err)
+	}
+}
+
+// askStream 用 body 构造问答流并读取全部事件
+func askStream(t *testing.T, contentType, body string) ([]QAStreamEvent, error) {
+	t.Helper()
+	resp := &http.Response{
+		Header: http.Header{"Content-Type": {contentType}},
+		Body:   io.NopCloser(strings.NewReader(body)),
+	}
+	stream := newStream[QAStreamEvent](context.Background(), resp, newTextStreamDecoder[QAStreamEvent](&qaStreamEvents{}))
+	defer stream.Close()
+
+	var events []QAStreamEvent
+	for stream.Next() {
+		events = append(events, stream.Current())
+	}
+	return events, stream.Err()
+}
+
+func TestQAStream(t *testing.T) {
+	ctx := []SearchResult{{ChunkID: "c1", Content: "RAG"}}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        []QAStreamEvent
+		wantErr     string // *StreamError 的错误信息
+		wantErrIs   error
+	}{
+		{
+			name:        "SSE with context, deltas and done",
+			contentType: "text/event-stream",
+			body: "event: context\ndata: {\"context\":[{\"chunk_id\":\"c1\",\"content\":\"RAG\"}]}\n\n" +
+				"data: {\"delta\":\"Hel\"}\n\n" +
+				"data: {\"type\":\"answer\",\"content\":\"lo\"}\n\n" +
+				"event: done\ndata: {}\n\n",
+			want: []QAStreamEvent{
+				{Type: QAEventContext, Context: ctx},
+				{Type: QAEventAnswer, Delta: "Hel"},
+				{Type: QAEventAnswer, Delta: "lo"},
+				{Type: QAEventDone, Answer: "Hello", Context: ctx},
+			},
+		},
+		{
+			name:        "NDJSON done event",
+			contentType: "application/x-ndjson",
+			body:        "{\"delta\":\"a\"}\n{\"delta\":\"b\"}\n{\"type\":\"done\"}",
+			want: []QAStreamEvent{
+				{Type: QAEventAnswer, Delta: "a"},
+				{Type: QAEventAnswer, Delta: "b"},
+				{Type: QAEventDone, Answer: "ab"},
+			},
+		},
+		{
+			name:        "truncated NDJSON stream",
+			contentType: "application/x-ndjson",
+			body:        "{\"delta\":\"a\"}\n{\"delta\":\"b\"}",
+			want: []QAStreamEvent{
+				{Type: QAEventAnswer, Delta: "a"},
+				{Type: QAEventAnswer, Delta: "b"},
+			},
+			wantErrIs: io.ErrUnexpectedEOF,
+		},
+		{
+			name:        "truncated SSE stream without [DONE]",
+			contentType: "text/event-stream",
+			body:        "data: {\"delta\":\"a\"}\n\n",
+			want:        []QAStreamEvent{{Type: QAEventAnswer, Delta: "a"}},
+			wantErrIs:   io.ErrUnexpectedEOF,
+		},
+		{
+			name:        "[DONE] without trailing blank line",
+			contentType: "text/event-stream",
+			body:        "data: {\"delta\":\"a\"}\n\ndata: [DONE]",
+			want: []QAStreamEvent{
+				{Type: QAEventAnswer, Delta: "a"},
+				{Type: QAEventDone, Answer: "a"},
+			},
+		},
+		{
+			name:        "plain text data is an answer delta",
+			contentType: "text/event-stream",
+			body:        "data: Hello\n\ndata: [DONE]\n\n",
+			want: []QAStreamEvent{
+				{Type: QAEventAnswer, Delta: "Hello"},
+				{Type: QAEventDone, Answer: "Hello"},
+			},
+		},
+		{
+			name:        "summary event replaces the answer",
+			contentType: "application/x-ndjson",
+			body:        "{\"delta\":\"draft\"}\n{\"type\":\"summary\",\"answer\":\"final\"}\n",
+			want: []QAStreamEvent{
+				{Type: QAEventAnswer, Delta: "draft"},
+				{Type: QAEventDone, Answer: "final"},
+			},
+		},
+		{
+			name:        "error event ends the stream",
+			contentType: "text/event-stream",
+			body:        "data: {\"delta\":\"a\"}\n\nevent: error\ndata: {\"message\":\"model unavailable\"}\n\ndata: {\"delta\":\"b\"}\n\n",
+			want: []QAStreamEvent{
+				{Type: QAEventAnswer, Delta: "a"},
+				{Type: QAEventError, Error: "model unavailable"},
+			},
+			wantErr: "stream error: model unavailable",
+		},
+		{
+			name:        "non-streaming JSON response",
+			contentType: "application/json",
+			body:        "{\"success\":true,\"data\":{\"answer\":\"Hello\",\"context\":[{\"chunk_id\":\"c1\",\"content\":\"RAG\"}]}}",
+			want: []QAStreamEvent{
+				{Type: QAEventContext, Context: ctx},
+				{Type: QAEventDone, Answer: "Hello", Context: ctx},
+			},
+		},
+		{
+			name:        "non-streaming JSON error",
+			contentType: "application/json",
+			body:        "{\"success\":false,\"message\":\"bad request\"}",
+			want:        []QAStreamEvent{{Type: QAEventError, Error: "bad request"}},
+			wantErr:     "stream error: bad request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := askStream(t, tt.contentType, tt.body)
+			if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+				t.Fatalf("Err() = %v, want %v", err, tt.wantErrIs)
+			}
+			if tt.wantErr == "" && tt.wantErrIs == nil && err != nil {
+				t.Fatalf("Err() = %v, want nil", err)
+			}
+			if tt.wantErr != "" {
+				var streamErr *StreamError
+				if !errors.As(err, &streamErr) || err.Error() != tt.wantErr {
+					t.Fatalf("Err() = %v, want *StreamError %q", err, tt.wantErr)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("events =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
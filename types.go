@@ -186,6 +186,14 @@ func (j *JSON) UnmarshalJSON(data []byte) error {
 	return json.Unmarshal(data, &j.Data)
 }
 
+// 对话消息角色
+const (
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+	ChatRoleSystem    = "system" // 补充给服务端的指令或背景，例如当前用户的身份，不属于问答轮次
+)
+
+// ChatMessage 对话消息，Role 为 ChatRoleUser、ChatRoleAssistant 或 ChatRoleSystem
 type ChatMessage struct {
 	Role    string `json:"role"`
 	Content string `json:"content"`
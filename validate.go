@@ -0,0 +1,150 @@
+package sdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError 请求在发送前未通过校验，Fields 包含所有不合法的字段
+//
+// errors.Is(err, ErrValidation) 为 true，与服务端返回的 400 错误可以统一处理。
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// Error 实现 error 接口
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i := range e.Fields {
+		fields[i] = e.Fields[i].Error()
+	}
+	return "invalid request: " + strings.Join(fields, "; ")
+}
+
+// Is 支持 errors.Is(err, ErrValidation)
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// validator 可以在发送前校验的请求
+type validator interface {
+	Validate() error
+}
+
+// validate 开启请求校验时调用 req.Validate
+func (c *Client) validate(req validator) error {
+	if c.skipValidation {
+		return nil
+	}
+	return req.Validate()
+}
+
+// validateIDs 检查路径参数不为空，参数按名称、值成对传入
+func (c *Client) validateIDs(pairs ...string) error {
+	if c.skipValidation {
+		return nil
+	}
+	var v validation
+	for i := 0; i+1 < len(pairs); i += 2 {
+		v.required(pairs[i], pairs[i+1])
+	}
+	return v.err()
+}
+
+// validation 收集字段错误
+type validation struct {
+	fields []FieldError
+}
+
+func (v *validation) addf(field, format string, args ...interface{}) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// required 字符串不能为空或只包含空白
+func (v *validation) required(field, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.addf(field, "is required")
+	}
+}
+
+// notEmpty 指针不为 nil 时指向的字符串不能为空
+func (v *validation) notEmpty(field string, value *string) {
+	if value != nil && strings.TrimSpace(*value) == "" {
+		v.addf(field, "must not be empty when set")
+	}
+}
+
+func (v *validation) nonNegative(field string, value int) {
+	if value < 0 {
+		v.addf(field, "must not be negative, got %d", value)
+	}
+}
+
+func (v *validation) between(field string, value, min, max float64) {
+	if value < min || value > max {
+		v.addf(field, "must be between %g and %g, got %g", min, max, value)
+	}
+}
+
+// page 页码不能为负数，每页数量只能是正数、0（默认值）或 PageSizeAll
+func (v *validation) page(page, pageSize int) {
+	v.nonNegative("page", page)
+	if pageSize < 0 && pageSize != PageSizeAll {
+		v.addf("page_size", "must be positive, 0 or PageSizeAll, got %d", pageSize)
+	}
+}
+
+// retrieval 检索参数，chat_history 的角色只能是 user、assistant 或 system，其他值多为拼写错误，在发送前拒绝
+func (v *validation) retrieval(topK int, mode string, threshold float64, history []ChatMessage) {
+	v.nonNegative("top_k", topK)
+	switch mode {
+	case "", RetrievalModeFull, RetrievalModeSmart:
+	default:
+		v.addf("retrieval_mode", "must be %q or %q, got %q", RetrievalModeFull, RetrievalModeSmart, mode)
+	}
+	v.between("similarity_threshold", threshold, 0, 1)
+	for i, msg := range history {
+		switch msg.Role {
+		case ChatRoleUser, ChatRoleAssistant, ChatRoleSystem:
+		default:
+			v.addf(fmt.Sprintf("chat_history[%d].role", i), "must be %q, %q or %q, got %q", ChatRoleUser, ChatRoleAssistant, ChatRoleSystem, msg.Role)
+		}
+	}
+}
+
+// datasetConfig 分块参数
+func (v *validation) datasetConfig(prefix string, c *DatasetConfig) {
+	v.nonNegative(prefix+"chunk_size", c.ChunkSize)
+	v.nonNegative(prefix+"chunk_overlap", c.ChunkOverlap)
+	if c.ChunkSize > 0 && c.ChunkOverlap >= c.ChunkSize {
+		v.addf(prefix+"chunk_overlap", "must be less than chunk_size (%d), got %d", c.ChunkSize, c.ChunkOverlap)
+	}
+}
+
+// modelConfig 模型参数
+func (v *validation) modelConfig(prefix string, c *AIModelConfig) {
+	if c.Temperature != nil {
+		v.between(prefix+"temperature", *c.Temperature, 0, 2)
+	}
+	if c.TopP != nil {
+		v.between(prefix+"top_p", *c.TopP, 0, 1)
+	}
+	if c.MaxTokens != nil && *c.MaxTokens <= 0 {
+		v.addf(prefix+"max_tokens", "must be positive, got %d", *c.MaxTokens)
+	}
+	v.nonNegative(prefix+"timeout", c.Timeout)
+	v.nonNegative(prefix+"max_retries", c.MaxRetries)
+}
+
+// err 没有字段错误时返回 nil
+func (v *validation) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// errNilRequest 请求为 nil 时返回的错误
+func errNilRequest() error {
+	return &ValidationError{Fields: []FieldError{{Message: "request must not be nil"}}}
+}
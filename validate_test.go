@@ -0,0 +1,134 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func ptr[T any](v T) *T { return &v }
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		req  validator
+		want []string // 出错的字段，nil 表示通过校验
+	}{
+		{"retrieve ok", &RetrieveRequest{DatasetID: "ds-1", Query: "q", TopK: 5, RetrievalMode: RetrievalModeSmart, SimilarityThreshold: 0.5}, nil},
+		{"retrieve missing fields", &RetrieveRequest{Query: "  "}, []string{"query", "dataset_id"}},
+		{"retrieve bad parameters", &RetrieveRequest{DatasetID: "ds-1", Query: "q", TopK: -1, RetrievalMode: "hybrid", SimilarityThreshold: 1.5},
+			[]string{"top_k", "retrieval_mode", "similarity_threshold"}},
+		{"chat history roles", &QARequest{DatasetID: "ds-1", Query: "q", ChatHistory: []ChatMessage{
+			{Role: ChatRoleSystem, Content: "answer in English"},
+			{Role: ChatRoleUser, Content: "hi"},
+			{Role: ChatRoleAssistant, Content: "hello"},
+			{Role: "User", Content: "typo"},
+			{Role: "", Content: "missing"},
+		}}, []string{"chat_history[3].role", "chat_history[4].role"}},
+		{"generate", &GenerateRequest{}, []string{"query"}},
+
+		{"create dataset ok", &CreateDatasetRequest{Name: "docs", Config: DatasetConfig{ChunkSize: 512, ChunkOverlap: 64}}, nil},
+		{"create dataset chunk overlap", &CreateDatasetRequest{Name: "docs", Config: DatasetConfig{ChunkSize: 100, ChunkOverlap: 100}}, []string{"config.chunk_overlap"}},
+		{"create dataset negative config", &CreateDatasetRequest{Config: DatasetConfig{ChunkSize: -1, ChunkOverlap: -1}},
+			[]string{"name", "config.chunk_size", "config.chunk_overlap"}},
+		{"update dataset empty name", &UpdateDatasetRequest{Name: ptr(" ")}, []string{"name"}},
+		{"update dataset unset fields", &UpdateDatasetRequest{}, nil},
+		{"list datasets nil", (*ListDatasetsRequest)(nil), nil},
+		{"list datasets page size all", &ListDatasetsRequest{PageSize: PageSizeAll}, nil},
+		{"list datasets bad paging", &ListDatasetsRequest{Page: -1, PageSize: -2}, []string{"page", "page_size"}},
+
+		{"upload", &UploadDocumentRequest{}, []string{"dataset_id", "filename", "file"}},
+		{"list documents", &ListDocumentsRequest{}, []string{"dataset_id"}},
+		{"batch delete empty", &BatchDeleteDocumentsRequest{DatasetID: "ds-1"}, []string{"document_ids"}},
+		{"batch delete blank id", &BatchDeleteDocumentsRequest{DatasetID: "ds-1", DocumentIDs: []string{"d1", ""}}, []string{"document_ids[1]"}},
+		{"update document", &UpdateDocumentRequest{DatasetID: "ds-1"}, []string{"document_id"}},
+
+		{"create model", &CreateModelRequest{}, []string{"name", "model_type", "provider", "model_name"}},
+		{"create model config", &CreateModelRequest{Name: "m", ModelType: "chat", Provider: "openai", ModelName: "gpt-4o", Config: AIModelConfig{
+			Temperature: ptr(2.5), TopP: ptr(-0.1), MaxTokens: ptr(0), Timeout: -1, MaxRetries: -1,
+		}}, []string{"config.temperature", "config.top_p", "config.max_tokens", "config.timeout", "config.max_retries"}},
+		{"update model", &UpdateModelRequest{Name: ptr(""), Config: &AIModelConfig{Temperature: ptr(1.0)}}, []string{"name"}},
+		{"check model", &CheckModelRequest{Provider: "openai"}, []string{"model_name"}},
+		{"provider models", &ListProviderModelsRequest{}, []string{"provider"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) || !errors.Is(err, ErrValidation) {
+				t.Fatalf("Validate() = %v, want *ValidationError", err)
+			}
+			var fields []string
+			for _, f := range verr.Fields {
+				fields = append(fields, f.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.want) {
+				t.Errorf("invalid fields = %q, want %q", fields, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNilRequest(t *testing.T) {
+	for _, req := range []validator{
+		(*RetrieveRequest)(nil),
+		(*QARequest)(nil),
+		(*GenerateRequest)(nil),
+		(*CreateDatasetRequest)(nil),
+		(*UpdateDatasetRequest)(nil),
+		(*UploadDocumentRequest)(nil),
+		(*ListDocumentsRequest)(nil),
+		(*BatchDeleteDocumentsRequest)(nil),
+		(*UpdateDocumentRequest)(nil),
+		(*CreateModelRequest)(nil),
+		(*UpdateModelRequest)(nil),
+		(*CheckModelRequest)(nil),
+		(*ListProviderModelsRequest)(nil),
+	} {
+		if err := req.Validate(); !errors.Is(err, ErrValidation) || !strings.Contains(err.Error(), "request must not be nil") {
+			t.Errorf("%T.Validate() = %v, want the nil request error", req, err)
+		}
+	}
+}
+
+func TestValidationErrorMessage(t *testing.T) {
+	err := (&RetrieveRequest{DatasetID: "ds-1", Query: "q", TopK: -3}).Validate()
+	want := "invalid request: top_k: must not be negative, got -3"
+	if err == nil || err.Error() != want {
+		t.Errorf("Error() = %v, want %q", err, want)
+	}
+}
+
+func TestClientValidation(t *testing.T) {
+	srv := newScriptedServer(t)
+	ctx := context.Background()
+
+	// 校验失败时不发送请求
+	client := newTestClient(t, srv.URL)
+	if _, err := client.Search.Retrieve(ctx, &RetrieveRequest{DatasetID: "ds-1"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+	if _, err := client.Datasets.Get(ctx, " "); !errors.Is(err, ErrValidation) {
+		t.Fatalf("Get with a blank ID err = %v, want ErrValidation", err)
+	}
+	if got := srv.count(); got != 0 {
+		t.Errorf("requests = %d, want 0", got)
+	}
+
+	// 关闭校验后交给服务端处理
+	client = newTestClient(t, srv.URL, WithValidation(false))
+	if _, err := client.Datasets.Create(ctx, &CreateDatasetRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
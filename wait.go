@@ -0,0 +1,204 @@
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 文档状态
+const (
+	DocumentStatusPending    = "pending"
+	DocumentStatusProcessing = "processing"
+	DocumentStatusCompleted  = "completed"
+	DocumentStatusFailed     = "failed"
+)
+
+// waitBatchSize 批量等待时每次 List 请求的最大文档数
+const waitBatchSize = 100
+
+// WaitOptions 等待文档处理完成的选项
+type WaitOptions struct {
+	// 首次轮询间隔，默认 1s
+	PollInterval time.Duration
+
+	// 轮询间隔上限，默认 30s
+	MaxInterval time.Duration
+
+	// 每次轮询后间隔的增长倍数，默认 1.5，设为 1 则固定间隔
+	Multiplier float64
+
+	// 文档状态或进度信息变化时调用
+	OnProgress func(doc *Document)
+}
+
+// DocumentFailedError 文档处理失败
+type DocumentFailedError struct {
+	Document Document
+}
+
+// Error 实现 error 接口
+func (e *DocumentFailedError) Error() string {
+	if e.Document.ProgressMsg != "" {
+		return fmt.Sprintf("document %s failed: %s", e.Document.ID, e.Document.ProgressMsg)
+	}
+	return fmt.Sprintf("document %s failed", e.Document.ID)
+}
+
+// poller 按退避间隔轮询
+type poller struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	multiplier  float64
+	onProgress  func(doc *Document)
+
+	// 上一次报告的状态和进度，用于判断是否变化
+	last map[string]string
+}
+
+func newPoller(opts *WaitOptions) *poller {
+	p := &poller{
+		interval:    time.Second,
+		maxInterval: 30 * time.Second,
+		multiplier:  1.5,
+		last:        make(map[string]string),
+	}
+	if opts != nil {
+		if opts.PollInterval > 0 {
+			p.interval = opts.PollInterval
+		}
+		if opts.MaxInterval > 0 {
+			p.maxInterval = opts.MaxInterval
+		}
+		if opts.Multiplier >= 1 {
+			p.multiplier = opts.Multiplier
+		}
+		p.onProgress = opts.OnProgress
+	}
+	if p.maxInterval < p.interval {
+		p.maxInterval = p.interval
+	}
+	return p
+}
+
+// wait 等待下一次轮询
+func (p *poller) wait(ctx context.Context) error {
+	timer := time.NewTimer(p.interval)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+	}
+
+	p.interval = time.Duration(float64(p.interval) * p.multiplier)
+	if p.interval > p.maxInterval {
+		p.interval = p.maxInterval
+	}
+	return nil
+}
+
+// report 在文档状态或进度变化时调用 OnProgress
+func (p *poller) report(doc *Document) {
+	if p.onProgress == nil {
+		return
+	}
+	state := doc.Status + "\x00" + doc.ProgressMsg
+	if last, ok := p.last[doc.ID]; ok && last == state {
+		return
+	}
+	p.last[doc.ID] = state
+	p.onProgress(doc)
+}
+
+// WaitUntilProcessed 轮询文档状态直到处理完成，callOpts 作用于每一次轮询请求
+// 文档处理失败时返回 *DocumentFailedError，ctx 结束时返回 ctx 的错误
+func (s *DocumentsService) WaitUntilProcessed(ctx context.Context, datasetID, documentID string, opts *WaitOptions, callOpts ...CallOption) (*Document, error) {
+	p := newPoller(opts)
+	for {
+		doc, err := s.Get(ctx, datasetID, documentID, callOpts...)
+		if err != nil {
+			return nil, err
+		}
+		p.report(doc)
+
+		switch doc.Status {
+		case DocumentStatusCompleted:
+			return doc, nil
+		case DocumentStatusFailed:
+			return doc, &DocumentFailedError{Document: *doc}
+		}
+
+		if err := p.wait(ctx); err != nil {
+			return doc, err
+		}
+	}
+}
+
+// WaitUntilAllProcessed 批量轮询多个文档直到全部处理结束
+// 使用 List 按文档 ID 过滤，每轮只查询尚未结束的文档，callOpts 作用于每一次 List 请求。
+// 返回的文档与 documentIDs 顺序一致；有文档处理失败时，返回的错误中包含每个失败文档的 *DocumentFailedError，
+// 可以用 errors.As 获取；有文档不存在时 errors.Is(err, ErrNotFound) 为 true。
+func (s *DocumentsService) WaitUntilAllProcessed(ctx context.Context, datasetID string, documentIDs []string, opts *WaitOptions, callOpts ...CallOption) ([]Document, error) {
+	p := newPoller(opts)
+	docs := make(map[string]Document, len(documentIDs))
+	pending := append([]string(nil), documentIDs...)
+
+	for len(pending) > 0 {
+		var remaining []string
+		for start := 0; start < len(pending); start += waitBatchSize {
+			end := start + waitBatchSize
+			if end > len(pending) {
+				end = len(pending)
+			}
+			batch := pending[start:end]
+
+			// 服务端可能限制每页数量，通过 Pager 读取这一批的全部结果
+			listed, err := s.ListPager(&ListDocumentsRequest{
+				DatasetID:   datasetID,
+				DocumentIDs: batch,
+			}, &PagerOptions{PageSize: len(batch)}, callOpts...).All(ctx)
+			if err != nil {
+				return nil, err
+			}
+
+			found := make(map[string]bool, len(listed))
+			for i := range listed {
+				doc := &listed[i]
+				found[doc.ID] = true
+				docs[doc.ID] = *doc
+				p.report(doc)
+
+				if doc.Status != DocumentStatusCompleted && doc.Status != DocumentStatusFailed {
+					remaining = append(remaining, doc.ID)
+				}
+			}
+			for _, id := range batch {
+				if !found[id] {
+					return nil, fmt.Errorf("document %s not found in dataset %s: %w", id, datasetID, ErrNotFound)
+				}
+			}
+		}
+
+		pending = remaining
+		if len(pending) == 0 {
+			break
+		}
+		if err := p.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]Document, 0, len(documentIDs))
+	var errs []error
+	for _, id := range documentIDs {
+		doc := docs[id]
+		result = append(result, doc)
+		if doc.Status == DocumentStatusFailed {
+			errs = append(errs, &DocumentFailedError{Document: doc})
+		}
+	}
+	return result, errors.Join(errs...)
+}